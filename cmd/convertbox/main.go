@@ -2,33 +2,53 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 
+	"github.com/g-laliotis/convertbox/internal/align"
 	"github.com/g-laliotis/convertbox/internal/config"
 	"github.com/g-laliotis/convertbox/internal/llm"
 	"github.com/g-laliotis/convertbox/internal/logger"
 	"github.com/g-laliotis/convertbox/internal/media"
+	"github.com/g-laliotis/convertbox/internal/textnorm"
 	"github.com/g-laliotis/convertbox/internal/tts"
+	"github.com/g-laliotis/convertbox/internal/visuals"
 )
 
 func main() {
 	// Load environment variables
 	_ = godotenv.Load()
 
+	if len(os.Args) > 1 && os.Args[1] == "voices" {
+		os.Exit(printVoices(os.Args[2:]))
+	}
+
 	// Parse command line flags
 	topic := flag.String("topic", "", "Video topic/title (required)")
 	output := flag.String("out", "build/final.mp4", "Output video path")
 	test := flag.Bool("test", false, "Run quick test mode")
+	prompt := flag.String("prompt", "", "Prompt template name in prompts/ or path to a .tmpl file (default from PROMPT_TEMPLATE)")
+	listModels := flag.Bool("list-models", false, "List models available from the configured LLM provider and exit")
+	multiPass := flag.Bool("multipass", false, "Render subtitles, logo and audio in separate ffmpeg passes (fallback for the single-pass render)")
+	var profileNames stringList
+	flag.Var(&profileNames, "profile", "Output profile to render, repeatable ("+strings.Join(media.Profiles(), ", ")+"); default uses the VIDEO_* settings")
 	flag.Parse()
 
+	if *listModels {
+		os.Exit(printModels())
+	}
+
 	if *topic == "" {
 		fmt.Println("Usage: convertbox --topic \"Your video topic\"")
+		fmt.Println("       convertbox voices [--model name]")
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
@@ -36,7 +56,19 @@ func main() {
 	// Initialize services
 	cfg := config.Load()
 	log := logger.New()
-	
+	if *prompt != "" {
+		cfg.PromptTemplate = *prompt
+	}
+	if *multiPass {
+		cfg.RenderMultiPass = true
+	}
+
+	targets, err := outputTargets(cfg, profileNames, *output)
+	if err != nil {
+		log.Error("%v", err)
+		os.Exit(1)
+	}
+
 	log.Info("🎬 Starting Convertbox for %s", cfg.ChannelName)
 	log.Info("Topic: %s", *topic)
 
@@ -49,67 +81,132 @@ func main() {
 	ctx := context.Background()
 
 	// Initialize services
-	llmService := llm.NewService(cfg, log)
+	llmService, err := llm.NewService(cfg, log)
+	if err != nil {
+		log.Error("LLM setup failed: %v", err)
+		os.Exit(1)
+	}
 	ttsService := tts.NewService(cfg, log)
 	mediaService := media.NewService(cfg, log)
+	alignService := align.NewService(cfg, log)
 
 	// Step 1: Generate script
 	log.Info("Step 1/5: Generating script...")
-	script, err := llmService.GenerateScript(ctx, *topic)
+	structured, attempts, err := llmService.GenerateScript(ctx, *topic)
+	attemptsJSON, _ := json.MarshalIndent(attempts, "", "  ")
+	if writeErr := os.WriteFile("build/script_attempts.json", attemptsJSON, 0644); writeErr != nil {
+		log.Warning("Failed to save script attempts: %v", writeErr)
+	}
 	if err != nil {
-		log.Error("Script generation failed: %v", err)
+		var lengthErr *llm.LengthError
+		if errors.As(err, &lengthErr) {
+			log.Error("Script never fit the length limits: %v", err)
+		} else {
+			log.Error("Script generation failed: %v", err)
+		}
+		log.Info("Attempt history: build/script_attempts.json")
 		os.Exit(1)
 	}
+	script := structured.Text()
+	segments := scriptSegments(structured)
 
 	scriptPath := "build/script.txt"
 	if err := os.WriteFile(scriptPath, []byte(script), 0644); err != nil {
 		log.Error("Failed to save script: %v", err)
 		os.Exit(1)
 	}
-	log.Success("Script saved (%d chars)", len(script))
+	scriptJSON, _ := json.MarshalIndent(structured, "", "  ")
+	if err := os.WriteFile("build/script.json", scriptJSON, 0644); err != nil {
+		log.Error("Failed to save script: %v", err)
+		os.Exit(1)
+	}
+	log.Success("Script saved: %q (%d chars)", structured.Title, len(script))
+
+	// Upload metadata is a convenience, so failures don't stop the render
+	metadata, err := llmService.GenerateMetadata(ctx, *topic, structured)
+	if err != nil {
+		log.Warning("Metadata generation failed: %v", err)
+	} else {
+		metadataJSON, _ := json.MarshalIndent(metadata, "", "  ")
+		if err := os.WriteFile("build/metadata.json", metadataJSON, 0644); err != nil {
+			log.Warning("Failed to save metadata: %v", err)
+		} else {
+			log.Success("Upload metadata saved to build/metadata.json")
+		}
+	}
 
 	// Step 2: Generate narration
 	log.Info("Step 2/5: Synthesizing narration...")
 	narrationPath := "build/narration.wav"
-	if err := ttsService.Synthesize(ctx, script, narrationPath); err != nil {
+	narration, err := ttsService.Synthesize(ctx, script, narrationPath)
+	if err != nil {
 		log.Error("TTS synthesis failed: %v", err)
 		os.Exit(1)
 	}
-	log.Success("Narration synthesized")
+	log.Success("Narration synthesized (%d sentences, %.1fs)", len(narration.Sentences), narration.Duration)
 
-	// Step 3: Create dynamic background video
-	log.Info("Step 3/5: Creating dynamic background...")
-	backgroundPath := "build/background.mp4"
-	duration := 65 * time.Second
-	if *test {
-		duration = 10 * time.Second // Faster for testing
-	}
-	
-	if err := mediaService.CreateDynamicBackground(ctx, script, backgroundPath, duration); err != nil {
-		log.Warning("Dynamic background failed, using static: %v", err)
-		if err := mediaService.CreateBackground(ctx, backgroundPath, duration); err != nil {
-			log.Error("Background creation failed: %v", err)
-			os.Exit(1)
-		}
+	// The video runs for the narration plus a short outro
+	narrationLength, err := mediaService.AudioDuration(narrationPath)
+	if err != nil {
+		log.Warning("Could not measure narration, using synthesis timings: %v", err)
+		narrationLength = seconds(narration.Duration)
 	}
-	log.Success("Background created")
+	videoLength := narrationLength + seconds(cfg.OutroPadding)
+	log.Info("Video length: %.1fs (%.1fs narration + %.1fs outro)", videoLength.Seconds(), narrationLength.Seconds(), cfg.OutroPadding)
 
-	// Step 4: Generate subtitles
-	log.Info("Step 4/5: Generating subtitles...")
-	subtitlesPath := "build/subtitles.srt"
-	if err := mediaService.GenerateSubtitles(narrationPath, script, subtitlesPath); err != nil {
+	// Step 3: Generate subtitles
+	log.Info("Step 3/5: Generating subtitles...")
+	words, method, err := alignService.Align(ctx, align.Request{
+		AudioPath: narrationPath,
+		Words:     media.TranscriptWords(segments),
+		Spans:     sentenceSpans(narration),
+		Duration:  seconds(narration.Duration),
+	})
+	var cues []media.Cue
+	if err == nil {
+		cues, err = mediaService.AlignedCaptions(segments, words)
+	}
+	burnFormat := cfg.CaptionFormat
+	if err != nil {
+		// Even split over the narration is the last resort, and karaoke
+		// highlighting would only show its guesses
+		log.Warning("Word alignment unavailable, spreading captions evenly: %v", err)
+		method = "even split"
+		burnFormat = "srt"
+		cues, err = mediaService.EvenCaptions(narrationPath, segments)
+	}
+	if err != nil {
 		log.Error("Subtitle generation failed: %v", err)
 		os.Exit(1)
 	}
-	log.Success("Subtitles generated")
+	log.Success("Subtitles generated (%d captions, %s)", len(cues), method)
 
-	// Step 5: Render final video
-	log.Info("Step 5/5: Rendering final video...")
-	renderCfg := media.RenderConfig{
-		VideoInputs: []string{backgroundPath},
-		Narration:   narrationPath,
-		CaptionsSRT: subtitlesPath,
-		Output:      *output,
+	// Backgrounds change with the narration's sentences
+	log.Info("Choosing background visuals...")
+	job := renderJob{
+		segments:    segments,
+		backgrounds: backgroundSegments(ctx, log, cfg, llmService, structured, narration),
+		narration:   narrationPath,
+		cues:        cues,
+		burnFormat:  burnFormat,
+		duration:    videoLength,
+	}
+	if *test && job.duration > 10*time.Second {
+		job.duration = 10 * time.Second // Faster for testing
+	}
+
+	// One asset index for every output, so all profiles show the same images
+	job.assets, err = media.LoadAssetIndex(cfg)
+	if err != nil {
+		log.Warning("Asset index unavailable, backgrounds will fall back to plain colour: %v", err)
+	} else {
+		clips := 0
+		for _, asset := range job.assets.Assets {
+			if asset.Kind == media.AssetVideo {
+				clips++
+			}
+		}
+		log.Info("Asset library: %d images, %d clips", len(job.assets.Assets)-clips, clips)
 	}
 
 	// Add logo if exists (check multiple formats and locations)
@@ -121,7 +218,7 @@ func main() {
 	}
 	for _, logoFile := range logoFiles {
 		if _, err := os.Stat(logoFile); err == nil {
-			renderCfg.Logo = logoFile
+			job.logo = logoFile
 			log.Info("Using logo: %s", logoFile)
 			break
 		}
@@ -134,18 +231,306 @@ func main() {
 	}
 	for _, musicFile := range musicFiles {
 		if _, err := os.Stat(musicFile); err == nil {
-			renderCfg.Music = musicFile
+			job.music = musicFile
 			log.Info("Using background music: %s", musicFile)
 			break
 		}
 	}
 
-	if err := mediaService.RenderVideo(ctx, renderCfg); err != nil {
-		log.Error("Video rendering failed: %v", err)
+	// Steps 4 and 5 run once per output profile
+	failed := 0
+	for _, target := range targets {
+		if target.profile != nil {
+			log.Info("Rendering %s profile (%dx%d, %s)", target.profile.Name, target.profile.Width, target.profile.Height, target.profile.Aspect)
+			if !target.profile.Fits(job.duration) {
+				log.Error("Video is %.1fs, %s allows at most %.0fs", job.duration.Seconds(), target.profile.Name, target.profile.MaxDuration.Seconds())
+				failed++
+				continue
+			}
+		} else if shorts, _ := media.LookupProfile("shorts"); target.config.VideoHeight > target.config.VideoWidth && !shorts.Fits(job.duration) {
+			log.Warning("Video is %.1fs, longer than the %.0fs YouTube Shorts limit", job.duration.Seconds(), shorts.MaxDuration.Seconds())
+		}
+		if err := renderTarget(ctx, log, target, job); err != nil {
+			log.Error("%v", err)
+			failed++
+			continue
+		}
+		log.Success("🎉 Video generated successfully!")
+		log.Info("Output: %s", filepath.ToSlash(target.output))
+	}
+	if err := job.assets.Save(); err != nil {
+		log.Warning("Failed to save asset index: %v", err)
+	}
+	if failed > 0 {
+		log.Error("%d of %d outputs failed", failed, len(targets))
 		os.Exit(1)
 	}
-
-	log.Success("🎉 Video generated successfully!")
-	log.Info("Output: %s", filepath.ToSlash(*output))
 	log.Info("Ready to upload to %s! 🚀", cfg.ChannelName)
-}
\ No newline at end of file
+}
+
+// renderJob holds what every output is rendered from
+type renderJob struct {
+	segments    []media.ScriptSegment
+	backgrounds []media.ScriptSegment // timed per sentence when the narration allows
+	narration   string
+	cues        []media.Cue
+	burnFormat  string
+	logo        string
+	music       string
+	assets      *media.AssetIndex
+	duration    time.Duration // length of the final video
+}
+
+// outputTarget is one video to render; profile is nil when the VIDEO_*
+// settings are used as configured
+type outputTarget struct {
+	profile  *media.Profile
+	config   *config.Config
+	buildDir string
+	output   string
+}
+
+// outputTargets resolves the --profile flags. With several profiles each
+// output gets the profile name appended, e.g. final_shorts.mp4.
+func outputTargets(cfg *config.Config, names []string, output string) ([]outputTarget, error) {
+	if len(names) == 0 {
+		return []outputTarget{{config: cfg, buildDir: "build", output: output}}, nil
+	}
+
+	var targets []outputTarget
+	for _, name := range names {
+		profile, err := media.LookupProfile(name)
+		if err != nil {
+			return nil, err
+		}
+		target := outputTarget{
+			profile:  &profile,
+			config:   profile.Apply(cfg),
+			buildDir: filepath.Join("build", profile.Name),
+			output:   output,
+		}
+		if len(names) > 1 {
+			ext := filepath.Ext(output)
+			target.output = strings.TrimSuffix(output, ext) + "_" + profile.Name + ext
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// renderTarget creates the background, burns the captions and renders one output
+func renderTarget(ctx context.Context, log *logger.Logger, target outputTarget, job renderJob) error {
+	mediaService := media.NewService(target.config, log)
+	if job.assets != nil {
+		mediaService.UseAssets(job.assets)
+	}
+	if err := os.MkdirAll(target.buildDir, 0755); err != nil {
+		return fmt.Errorf("failed to create build directory: %w", err)
+	}
+
+	// Step 4: Create dynamic background video
+	log.Info("Step 4/5: Creating dynamic background...")
+	backgroundPath := filepath.Join(target.buildDir, "background.mp4")
+	if err := mediaService.CreateSegmentedBackground(ctx, job.backgrounds, target.buildDir, backgroundPath, job.duration); err != nil {
+		log.Warning("Dynamic background failed, using static: %v", err)
+		if err := mediaService.CreateBackground(ctx, backgroundPath, job.duration); err != nil {
+			return fmt.Errorf("background creation failed: %w", err)
+		}
+	}
+	log.Success("Background created")
+
+	// Step 5: Render final video
+	log.Info("Step 5/5: Rendering final video...")
+	subtitlesPath := filepath.Join(target.buildDir, "subtitles.srt")
+	if err := mediaService.WriteCaptions(job.cues, "srt", subtitlesPath); err != nil {
+		return fmt.Errorf("subtitle generation failed: %w", err)
+	}
+	var assPath string
+	if job.burnFormat == "ass" {
+		assPath = filepath.Join(target.buildDir, "subtitles.ass")
+		if err := mediaService.WriteCaptions(job.cues, "ass", assPath); err != nil {
+			log.Warning("Styled captions failed, burning plain subtitles: %v", err)
+			assPath = ""
+		}
+	}
+
+	renderCfg := media.RenderConfig{
+		VideoInputs: []string{backgroundPath},
+		Narration:   job.narration,
+		Music:       job.music,
+		Logo:        job.logo,
+		CaptionsSRT: subtitlesPath,
+		CaptionsASS: assPath,
+		Output:      target.output,
+		WorkDir:     target.buildDir,
+		Duration:    job.duration,
+	}
+	if err := os.MkdirAll(filepath.Dir(target.output), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := mediaService.RenderVideo(ctx, renderCfg); err != nil {
+		return fmt.Errorf("video rendering failed: %w", err)
+	}
+
+	// Caption tracks for upload sit next to the video with the same name
+	base := strings.TrimSuffix(target.output, filepath.Ext(target.output))
+	for _, format := range target.config.CaptionExports {
+		path := base + "." + format
+		if err := mediaService.WriteCaptions(job.cues, format, path); err != nil {
+			log.Warning("Caption export failed: %v", err)
+			continue
+		}
+		log.Info("Captions: %s", filepath.ToSlash(path))
+	}
+	return nil
+}
+
+// stringList collects a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// printModels lists the provider's models and returns the process exit code
+func printModels() int {
+	cfg := config.Load()
+	log := logger.New()
+
+	llmService, err := llm.NewService(cfg, log)
+	if err != nil {
+		log.Error("LLM setup failed: %v", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	models, err := llmService.ListModels(ctx)
+	if err != nil {
+		log.Error("Listing models failed: %v", err)
+		return 1
+	}
+	for _, model := range models {
+		fmt.Println(model)
+	}
+	return 0
+}
+
+// printVoices lists Coqui models and the speakers/languages of one model
+func printVoices(args []string) int {
+	cfg := config.Load()
+	log := logger.New()
+
+	fs := flag.NewFlagSet("voices", flag.ExitOnError)
+	model := fs.String("model", cfg.CoquiModel, "Model whose speakers and languages to list")
+	fs.Parse(args)
+
+	ctx := context.Background()
+	ttsService := tts.NewService(cfg, log)
+
+	models, err := ttsService.ListModels(ctx)
+	if err != nil {
+		log.Error("Listing Coqui models failed: %v", err)
+		return 1
+	}
+	fmt.Println("Models:")
+	for _, m := range models {
+		fmt.Printf("  %s\n", m)
+	}
+
+	// Single-speaker and monolingual models make these commands fail, so
+	// only report what is available
+	if speakers, err := ttsService.ListSpeakers(ctx, *model); err == nil && len(speakers) > 0 {
+		fmt.Printf("\nSpeakers for %s (COQUI_SPEAKER):\n", *model)
+		for _, speaker := range speakers {
+			fmt.Printf("  %s\n", speaker)
+		}
+	}
+	if languages, err := ttsService.ListLanguages(ctx, *model); err == nil && len(languages) > 0 {
+		fmt.Printf("\nLanguages for %s (COQUI_LANGUAGE):\n", *model)
+		for _, language := range languages {
+			fmt.Printf("  %s\n", language)
+		}
+	}
+	return 0
+}
+
+// scriptSegments converts the structured script into the media package's segments
+func scriptSegments(script *llm.Script) []media.ScriptSegment {
+	var segments []media.ScriptSegment
+	for _, segment := range script.Segments() {
+		segments = append(segments, media.ScriptSegment{
+			Text:       segment.Text,
+			Keywords:   segment.Keywords,
+			Transition: segment.Transition,
+		})
+	}
+	return segments
+}
+
+// backgroundSegments splits the script into the narration's sentences, each
+// timed by the manifest and tagged with its beat's keywords plus the visual
+// keywords from the LLM, or TF-IDF when VISUAL_KEYWORDS=tfidf or the model
+// fails. It keeps the script's segments, timed by word share, when the
+// sentences don't line up with the narration.
+func backgroundSegments(ctx context.Context, log *logger.Logger, cfg *config.Config, llmService *llm.Service, script *llm.Script, narration *tts.Manifest) []media.ScriptSegment {
+	var sentences []media.ScriptSegment
+	for _, segment := range script.Segments() {
+		for i, sentence := range textnorm.SplitSentences(segment.Text) {
+			next := media.ScriptSegment{Text: sentence, Keywords: segment.Keywords}
+			if i == 0 {
+				next.Transition = segment.Transition // the beat's transition leads into its first sentence
+			}
+			sentences = append(sentences, next)
+		}
+	}
+	if len(sentences) != len(narration.Sentences) {
+		log.Warning("Script has %d sentences but the narration %d, timing backgrounds by script section", len(sentences), len(narration.Sentences))
+		return scriptSegments(script)
+	}
+
+	texts := make([]string, len(sentences))
+	for i, sentence := range sentences {
+		texts[i] = sentence.Text
+	}
+	var keywords [][]string
+	if cfg.VisualKeywords == "llm" {
+		var err error
+		if keywords, err = llmService.ExtractVisuals(ctx, texts); err != nil {
+			log.Warning("Visual keyword extraction failed, using TF-IDF: %v", err)
+		}
+	}
+	if keywords == nil {
+		keywords = visuals.Extract(texts, 3)
+	}
+
+	for i := range sentences {
+		sentences[i].Keywords = append(append([]string{}, sentences[i].Keywords...), keywords[i]...)
+		sentences[i].Start = seconds(narration.Sentences[i].Start)
+		sentences[i].End = seconds(narration.Sentences[i].End)
+	}
+	return sentences
+}
+
+// sentenceSpans converts the narration's sentence timings for alignment
+func sentenceSpans(narration *tts.Manifest) []align.Span {
+	var spans []align.Span
+	for _, sentence := range narration.Sentences {
+		spans = append(spans, align.Span{
+			Text:  sentence.Text,
+			Start: seconds(sentence.Start),
+			End:   seconds(sentence.End),
+		})
+	}
+	return spans
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
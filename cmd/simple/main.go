@@ -8,6 +8,8 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/g-laliotis/convertbox/internal/textnorm"
 )
 
 func main() {
@@ -58,14 +60,17 @@ func generateScript(topic string) string {
 	if len(script) == 0 {
 		script = fmt.Sprintf("Welcome to A.I. Unboxed by UnboxGio! Today we explore %s. This amazing topic in artificial intelligence is revolutionizing our world. These tools boost productivity and creativity. The future is powered by A.I. Subscribe for more A.I. insights!", topic)
 	}
-	// Fix AI pronunciation
-	script = strings.ReplaceAll(script, " AI ", " A.I. ")
-	script = strings.ReplaceAll(script, "AI ", "A.I. ")
-	script = strings.ReplaceAll(script, " AI.", " A.I.")
 	return script
 }
 
 func textToSpeech(text string) {
+	// Fix pronunciation of acronyms, numbers and URLs for narration only
+	normalizer, err := textnorm.Load("assets/lexicon.txt")
+	if err != nil {
+		normalizer = textnorm.New(nil)
+	}
+	text = normalizer.Normalize(text)
+
 	// Try Coqui with better model
 	cmd := exec.Command("tts", "--text", text, "--model_name", "tts_models/en/ljspeech/tacotron2-DDC", "--out_path", "build/speech.wav")
 	if cmd.Run() != nil {
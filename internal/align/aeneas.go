@@ -0,0 +1,95 @@
+package align
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+)
+
+// AeneasAligner force-aligns the transcript to the narration with aeneas,
+// one fragment per word
+type AeneasAligner struct {
+	config *config.Config
+}
+
+type aeneasOutput struct {
+	Fragments []struct {
+		Begin string   `json:"begin"`
+		End   string   `json:"end"`
+		Lines []string `json:"lines"`
+	} `json:"fragments"`
+}
+
+func NewAeneasAligner(cfg *config.Config) *AeneasAligner {
+	return &AeneasAligner{config: cfg}
+}
+
+func (a *AeneasAligner) Name() string {
+	return "aeneas"
+}
+
+func (a *AeneasAligner) Align(ctx context.Context, req Request) ([]Word, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Minute)
+	defer cancel()
+
+	workDir := filepath.Dir(req.AudioPath)
+	textPath := filepath.Join(workDir, "align_words.txt")
+	outPath := filepath.Join(workDir, "align_aeneas.json")
+	if err := os.WriteFile(textPath, []byte(strings.Join(req.Words, "\n")), 0644); err != nil {
+		return nil, err
+	}
+
+	task := fmt.Sprintf("task_language=%s|is_text_type=plain|os_task_file_format=json", a.config.AeneasLanguage)
+	var errOut bytes.Buffer
+	cmd := exec.CommandContext(ctx, a.config.AeneasPython, "-m", "aeneas.tools.execute_task",
+		req.AudioPath, textPath, task, outPath)
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		// aeneas prints a traceback; its last line names the problem
+		lines := strings.Split(strings.TrimSpace(errOut.String()), "\n")
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(lines[len(lines)-1]))
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		return nil, err
+	}
+	return parseAeneasJSON(data, req.Words)
+}
+
+func parseAeneasJSON(data []byte, transcript []string) ([]Word, error) {
+	var out aeneasOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("decode aeneas output: %w", err)
+	}
+	if len(out.Fragments) != len(transcript) {
+		return nil, fmt.Errorf("aeneas returned %d fragments for %d words", len(out.Fragments), len(transcript))
+	}
+
+	words := make([]Word, len(transcript))
+	for i, fragment := range out.Fragments {
+		begin, err := strconv.ParseFloat(fragment.Begin, 64)
+		if err != nil {
+			return nil, fmt.Errorf("fragment %d: %w", i, err)
+		}
+		end, err := strconv.ParseFloat(fragment.End, 64)
+		if err != nil {
+			return nil, fmt.Errorf("fragment %d: %w", i, err)
+		}
+		words[i] = Word{
+			Text:  transcript[i],
+			Start: time.Duration(begin * float64(time.Second)),
+			End:   time.Duration(end * float64(time.Second)),
+		}
+	}
+	return words, nil
+}
@@ -0,0 +1,101 @@
+// Package align produces per-word timestamps for the narration so captions
+// follow the actual speech instead of an even split.
+package align
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+	"github.com/g-laliotis/convertbox/internal/logger"
+)
+
+// Word is a transcript word and when it is spoken
+type Word struct {
+	Text  string        `json:"text"`
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+}
+
+// Span is a stretch of text with known timing, e.g. one synthesized sentence
+type Span struct {
+	Text  string
+	Start time.Duration
+	End   time.Duration
+}
+
+// Request describes the narration to align
+type Request struct {
+	AudioPath string
+	Words     []string
+	Spans     []Span
+	Duration  time.Duration
+}
+
+// Aligner returns exactly one timing per word in req.Words
+type Aligner interface {
+	Name() string
+	Align(ctx context.Context, req Request) ([]Word, error)
+}
+
+type Service struct {
+	config   *config.Config
+	logger   *logger.Logger
+	aligners []Aligner
+}
+
+func NewService(cfg *config.Config, log *logger.Logger) *Service {
+	var aligners []Aligner
+	for _, name := range cfg.Aligners {
+		switch strings.ToLower(name) {
+		case "whisper":
+			aligners = append(aligners, NewWhisperAligner(cfg))
+		case "aeneas":
+			aligners = append(aligners, NewAeneasAligner(cfg))
+		case "manifest":
+			aligners = append(aligners, SpanAligner{})
+		default:
+			log.Warning("Unknown aligner %q (available: whisper, aeneas, manifest)", name)
+		}
+	}
+
+	return &Service{
+		config:   cfg,
+		logger:   log,
+		aligners: aligners,
+	}
+}
+
+// Align tries each configured aligner in order and returns the first
+// complete result along with the name of the aligner that produced it
+func (s *Service) Align(ctx context.Context, req Request) ([]Word, string, error) {
+	if len(req.Words) == 0 {
+		return nil, "", errors.New("no words to align")
+	}
+
+	var failures []string
+	for _, aligner := range s.aligners {
+		words, err := aligner.Align(ctx, req)
+		if err == nil && len(words) != len(req.Words) {
+			err = fmt.Errorf("returned %d timings for %d words", len(words), len(req.Words))
+		}
+		if err == nil {
+			s.logger.Info("Aligned %d words with %s", len(words), aligner.Name())
+			return words, aligner.Name(), nil
+		}
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+
+		s.logger.Warning("%s alignment failed: %v", aligner.Name(), err)
+		failures = append(failures, fmt.Sprintf("%s: %v", aligner.Name(), err))
+	}
+
+	if len(failures) == 0 {
+		return nil, "", errors.New("no aligners configured")
+	}
+	return nil, "", fmt.Errorf("all aligners failed (%s)", strings.Join(failures, "; "))
+}
@@ -0,0 +1,146 @@
+package align
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+	"github.com/g-laliotis/convertbox/internal/logger"
+)
+
+const ms = time.Millisecond
+
+func TestSpanAligner(t *testing.T) {
+	req := Request{
+		Words: []string{"Hi", "there.", "Go", "now!"},
+		Spans: []Span{
+			{Text: "Hi there.", Start: 0, End: 1000 * ms},
+			{Text: "Go now!", Start: 1500 * ms, End: 2300 * ms},
+		},
+	}
+
+	words, err := SpanAligner{}.Align(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Align failed: %v", err)
+	}
+
+	// "Hi" weighs 3 of 10 characters in the first span, "Go" 3 of 8 in the second
+	want := []Word{
+		{Text: "Hi", Start: 0, End: 300 * ms},
+		{Text: "there.", Start: 300 * ms, End: 1000 * ms},
+		{Text: "Go", Start: 1500 * ms, End: 1800 * ms},
+		{Text: "now!", Start: 1800 * ms, End: 2300 * ms},
+	}
+	for i := range want {
+		if words[i] != want[i] {
+			t.Errorf("word %d = %+v, want %+v", i, words[i], want[i])
+		}
+	}
+
+	req.Spans = req.Spans[:1]
+	if _, err := (SpanAligner{}).Align(context.Background(), req); err == nil {
+		t.Error("expected error when spans don't cover every word")
+	}
+}
+
+func TestMatchRecognized(t *testing.T) {
+	transcript := []string{"In", "2025,", "A.I.", "changed", "everything."}
+	recognized := []Word{
+		{Text: "In", Start: 0, End: 200 * ms},
+		{Text: "twenty", Start: 200 * ms, End: 500 * ms},
+		{Text: "twenty-five,", Start: 500 * ms, End: 900 * ms},
+		{Text: "AI", Start: 1000 * ms, End: 1300 * ms},
+		{Text: "changed", Start: 1300 * ms, End: 1700 * ms},
+		{Text: "everything.", Start: 1700 * ms, End: 2400 * ms},
+	}
+
+	words, err := matchRecognized(transcript, recognized, 2500*ms)
+	if err != nil {
+		t.Fatalf("matchRecognized failed: %v", err)
+	}
+
+	if words[2].Start != 1000*ms || words[4].End != 2400*ms {
+		t.Errorf("matched words should keep recognized timings: %+v", words)
+	}
+	// "2025," is unmatched and fills the gap between "In" and "A.I."
+	if words[1].Start != 200*ms || words[1].End != 1000*ms || words[1].Text != "2025," {
+		t.Errorf("gap word = %+v", words[1])
+	}
+}
+
+func TestMatchRecognized_TooFewMatches(t *testing.T) {
+	transcript := []string{"one", "two", "three", "four"}
+	recognized := []Word{{Text: "banana", End: time.Second}, {Text: "four", Start: time.Second, End: 2 * time.Second}}
+
+	if _, err := matchRecognized(transcript, recognized, 0); err == nil {
+		t.Error("expected error when recognition barely matches")
+	}
+}
+
+func TestParseWhisperJSON(t *testing.T) {
+	data := []byte(`{"transcription":[
+		{"offsets":{"from":0,"to":0},"text":""},
+		{"offsets":{"from":0,"to":420},"text":" Hello"},
+		{"offsets":{"from":420,"to":900},"text":" world."},
+		{"offsets":{"from":900,"to":1200},"text":" [MUSIC]"}
+	]}`)
+
+	words, err := parseWhisperJSON(data)
+	if err != nil {
+		t.Fatalf("parseWhisperJSON failed: %v", err)
+	}
+	if len(words) != 2 || words[1].Text != "world." || words[1].Start != 420*ms || words[1].End != 900*ms {
+		t.Errorf("words = %+v", words)
+	}
+}
+
+func TestParseAeneasJSON(t *testing.T) {
+	data := []byte(`{"fragments":[
+		{"begin":"0.000","end":"0.380","id":"f000001","lines":["Hello"]},
+		{"begin":"0.380","end":"1.040","id":"f000002","lines":["world."]}
+	]}`)
+
+	words, err := parseAeneasJSON(data, []string{"Hello", "world."})
+	if err != nil {
+		t.Fatalf("parseAeneasJSON failed: %v", err)
+	}
+	if words[1].Start != 380*ms || words[1].End != 1040*ms {
+		t.Errorf("words = %+v", words)
+	}
+
+	if _, err := parseAeneasJSON(data, []string{"Hello"}); err == nil {
+		t.Error("expected error on fragment count mismatch")
+	}
+}
+
+// stubAligner returns a fixed result
+type stubAligner struct {
+	name  string
+	words []Word
+	err   error
+}
+
+func (a stubAligner) Name() string { return a.name }
+
+func (a stubAligner) Align(ctx context.Context, req Request) ([]Word, error) {
+	return a.words, a.err
+}
+
+func TestService_AlignFallsBack(t *testing.T) {
+	service := NewService(&config.Config{}, logger.New())
+	service.aligners = []Aligner{
+		stubAligner{name: "broken", err: errors.New("boom")},
+		stubAligner{name: "short", words: []Word{{Text: "a"}}},
+		stubAligner{name: "good", words: []Word{{Text: "a"}, {Text: "b"}}},
+	}
+
+	words, method, err := service.Align(context.Background(), Request{Words: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("Align failed: %v", err)
+	}
+	if method != "good" || len(words) != 2 {
+		t.Errorf("method = %s, words = %+v", method, words)
+	}
+}
@@ -0,0 +1,112 @@
+package align
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// minMatchRatio is the share of transcript words a recognizer must find for
+// its timings to be trusted
+const minMatchRatio = 0.5
+
+// matchRecognized maps words recognized by a speech model onto the
+// transcript. Matching words take the recognized timing; the rest (numbers
+// spelled out by TTS, misheard words) are spread between their neighbours.
+func matchRecognized(transcript []string, recognized []Word, duration time.Duration) ([]Word, error) {
+	n, m := len(transcript), len(recognized)
+	a := make([]string, n)
+	for i, word := range transcript {
+		a[i] = matchKey(word)
+	}
+	b := make([]string, m)
+	for j, word := range recognized {
+		b[j] = matchKey(word.Text)
+	}
+
+	// Longest common subsequence table, filled from the end
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case a[i] != "" && a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	if n == 0 || float64(lcs[0][0]) < minMatchRatio*float64(n) {
+		return nil, fmt.Errorf("recognized speech matches only %d of %d transcript words", lcs[0][0], n)
+	}
+
+	result := make([]Word, n)
+	matched := make([]bool, n)
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case a[i] != "" && a[i] == b[j]:
+			result[i] = Word{Text: transcript[i], Start: recognized[j].Start, End: recognized[j].End}
+			matched[i] = true
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	if duration <= 0 && m > 0 {
+		duration = recognized[m-1].End
+	}
+	fillGaps(transcript, result, matched, duration)
+	return result, nil
+}
+
+// fillGaps distributes each run of unmatched words between the matched
+// words around it
+func fillGaps(transcript []string, result []Word, matched []bool, duration time.Duration) {
+	for i := 0; i < len(transcript); {
+		if matched[i] {
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(transcript) && !matched[j] {
+			j++
+		}
+
+		var start, end time.Duration
+		if i > 0 {
+			start = result[i-1].End
+		}
+		end = duration
+		if j < len(transcript) {
+			end = result[j].Start
+		}
+		if end < start {
+			end = start
+		}
+
+		copy(result[i:j], distribute(transcript[i:j], start, end))
+		i = j
+	}
+}
+
+// matchKey lowercases a word and drops punctuation so "A.I.," matches "ai"
+func matchKey(word string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, word)
+}
@@ -0,0 +1,59 @@
+package align
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SpanAligner spreads each span's duration over its words by length. With
+// the sentence timings from chunked TTS this follows every pause between
+// sentences, which is what drifts most with an even split.
+type SpanAligner struct{}
+
+func (SpanAligner) Name() string {
+	return "manifest"
+}
+
+func (SpanAligner) Align(ctx context.Context, req Request) ([]Word, error) {
+	if len(req.Spans) == 0 {
+		return nil, errors.New("no sentence timings available")
+	}
+
+	result := make([]Word, 0, len(req.Words))
+	for _, span := range req.Spans {
+		count := len(strings.Fields(strings.ReplaceAll(span.Text, `"`, "")))
+		if len(result)+count > len(req.Words) {
+			return nil, fmt.Errorf("sentence timings cover more than the %d transcript words", len(req.Words))
+		}
+		result = append(result, distribute(req.Words[len(result):len(result)+count], span.Start, span.End)...)
+	}
+
+	if len(result) != len(req.Words) {
+		return nil, fmt.Errorf("sentence timings cover %d of %d words", len(result), len(req.Words))
+	}
+	return result, nil
+}
+
+// distribute shares [start, end) between words in proportion to their length
+func distribute(words []string, start, end time.Duration) []Word {
+	total := 0
+	for _, word := range words {
+		total += len(word) + 1
+	}
+
+	result := make([]Word, len(words))
+	elapsed := 0
+	for i, word := range words {
+		wordStart := start + (end-start)*time.Duration(elapsed)/time.Duration(total)
+		elapsed += len(word) + 1
+		result[i] = Word{
+			Text:  word,
+			Start: wordStart,
+			End:   start + (end-start)*time.Duration(elapsed)/time.Duration(total),
+		}
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+package align
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+)
+
+// WhisperAligner transcribes the narration with a local whisper.cpp binary
+// and maps the recognized word timings onto the transcript
+type WhisperAligner struct {
+	config *config.Config
+}
+
+type whisperOutput struct {
+	Transcription []struct {
+		Offsets struct {
+			From int64 `json:"from"`
+			To   int64 `json:"to"`
+		} `json:"offsets"`
+		Text string `json:"text"`
+	} `json:"transcription"`
+}
+
+func NewWhisperAligner(cfg *config.Config) *WhisperAligner {
+	return &WhisperAligner{config: cfg}
+}
+
+func (a *WhisperAligner) Name() string {
+	return "whisper"
+}
+
+func (a *WhisperAligner) Align(ctx context.Context, req Request) ([]Word, error) {
+	if _, err := exec.LookPath(a.config.WhisperBinary); err != nil {
+		return nil, fmt.Errorf("whisper.cpp not installed: %w", err)
+	}
+	if _, err := os.Stat(a.config.WhisperModel); err != nil {
+		return nil, fmt.Errorf("whisper model not available: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Minute)
+	defer cancel()
+
+	// whisper.cpp only accepts 16 kHz mono input
+	workDir := filepath.Dir(req.AudioPath)
+	resampled := filepath.Join(workDir, "align_16k.wav")
+	if err := exec.CommandContext(ctx, "ffmpeg", "-y", "-i", req.AudioPath,
+		"-ar", "16000", "-ac", "1", "-c:a", "pcm_s16le", resampled).Run(); err != nil {
+		return nil, fmt.Errorf("resample for whisper: %w", err)
+	}
+
+	outBase := filepath.Join(workDir, "align_whisper")
+	var errOut bytes.Buffer
+	cmd := exec.CommandContext(ctx, a.config.WhisperBinary,
+		"-m", a.config.WhisperModel,
+		"-f", resampled,
+		"-ml", "1", "-sow", // one word per segment
+		"-oj", "-of", outBase,
+		"-np",
+	)
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(errOut.String()))
+	}
+
+	data, err := os.ReadFile(outBase + ".json")
+	if err != nil {
+		return nil, err
+	}
+	recognized, err := parseWhisperJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return matchRecognized(req.Words, recognized, req.Duration)
+}
+
+func parseWhisperJSON(data []byte) ([]Word, error) {
+	var out whisperOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("decode whisper output: %w", err)
+	}
+
+	var words []Word
+	for _, segment := range out.Transcription {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" || strings.HasPrefix(text, "[") {
+			continue // blank segments and markers like [MUSIC]
+		}
+		words = append(words, Word{
+			Text:  text,
+			Start: time.Duration(segment.Offsets.From) * time.Millisecond,
+			End:   time.Duration(segment.Offsets.To) * time.Millisecond,
+		})
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("whisper recognized no words")
+	}
+	return words, nil
+}
@@ -3,44 +3,213 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
 	// LLM Configuration
-	OllamaModel string
-	OllamaHost  string
+	LLMProvider       string
+	OllamaModel       string
+	OllamaHost        string
+	LlamaCppHost      string
+	OpenAIBaseURL     string
+	OpenAIModel       string
+	OpenAIAPIKey      string
+	LLMTemperature    float64
+	LLMTopP           float64
+	LLMSeed           int
+	LLMMaxTokens      int
+	ScriptMaxAttempts int
+	ScriptMinWords    int
+	ScriptMaxWords    int
+	ScriptMaxSeconds  int
+	ScriptWPM         int
+	PromptDir         string
+	PromptTemplate    string
+	ScriptTone        string
+	ScriptCTA         string
+	ScriptLanguage    string
 
 	// TTS Configuration
-	TTSEngine    string
-	CoquiModel   string
-	ESpeakVoice  string
-	ESpeakSpeed  int
+	TTSEngine          string
+	TTSEngines         []string
+	TTSSentencePauseMs int
+	CoquiModel         string
+	CoquiSpeaker       string
+	CoquiLanguage      string
+	CoquiSpeakerWav    string
+	CoquiPython        string
+	CoquiBatch         bool
+	ESpeakVoice        string
+	ESpeakSpeed        int
+	LexiconPath        string
+	PiperBinary        string
+	PiperModel         string
+	PiperConfig        string
+	PiperSpeaker       string
+	PiperLengthScale   float64
+	PiperNoiseScale    float64
+	PiperNoiseW        float64
+
+	// Caption Alignment
+	Aligners       []string
+	WhisperBinary  string
+	WhisperModel   string
+	AeneasPython   string
+	AeneasLanguage string
+
+	// Caption Style
+	CaptionFormat         string
+	CaptionExports        []string
+	CaptionFont           string
+	CaptionFontSize       int
+	CaptionOutline        int
+	CaptionShadow         int
+	CaptionColor          string
+	CaptionHighlightColor string
+	CaptionPosition       string
+	CaptionMargin         int
+	CaptionMaxChars       int
+	CaptionMaxLines       int
+	CaptionMinSeconds     float64
+	CaptionMaxSeconds     float64
+	CaptionMaxCPS         float64
 
 	// Video Configuration
-	VideoWidth   int
-	VideoHeight  int
-	VideoCRF     int
-	VideoPreset  string
-	LogoMargin   int
+	VideoWidth           int
+	VideoHeight          int
+	VideoCRF             int
+	VideoPreset          string
+	VideoFPS             int
+	VideoMaxRate         string
+	AudioBitrate         string
+	LoudnessTarget       float64
+	LoudnessTruePeak     float64
+	LoudnessRange        float64
+	RenderMultiPass      bool
+	OutroPadding         float64
+	Transitions          []string
+	TransitionDuration   float64
+	BackgroundSeed       int64
+	AssetDir             string
+	AssetIndexPath       string
+	AssetRecentRuns      int
+	AssetVideos          bool
+	KeywordMapPath       string
+	VisualKeywords       string
+	BackgroundMinSeconds float64
+
+	// Music
+	MusicLevelDB  float64
+	MusicFadeIn   float64
+	MusicFadeOut  float64
+	DuckThreshold float64
+	DuckRatio     float64
+	DuckAttackMs  float64
+	DuckReleaseMs float64
+	LogoMargin    int
 
 	// Branding
-	ChannelName string
+	ChannelName        string
+	ChannelDescription string
+	MetadataFooter     string
 }
 
 func Load() *Config {
 	return &Config{
-		OllamaModel:  getEnv("OLLAMA_MODEL", "mistral"),
-		OllamaHost:   getEnv("OLLAMA_HOST", "http://localhost:11434"),
-		TTSEngine:    getEnv("TTS_ENGINE", "coqui"),
-		CoquiModel:   getEnv("COQUI_MODEL", "tts_models/en/vctk/vits"),
-		ESpeakVoice:  getEnv("ESPEAK_VOICE", "en-us"),
-		ESpeakSpeed:  getEnvInt("ESPEAK_SPEED", 160),
-		VideoWidth:   getEnvInt("VIDEO_WIDTH", 1080),
-		VideoHeight:  getEnvInt("VIDEO_HEIGHT", 1920),
-		VideoCRF:     getEnvInt("VIDEO_CRF", 18),
-		VideoPreset:  getEnv("VIDEO_PRESET", "veryfast"),
-		LogoMargin:   getEnvInt("LOGO_MARGIN", 40),
-		ChannelName:  getEnv("CHANNEL_NAME", "AI Unboxed by UnboxGio"),
+		LLMProvider:           getEnv("LLM_PROVIDER", "ollama"),
+		OllamaModel:           getEnv("OLLAMA_MODEL", "mistral"),
+		OllamaHost:            getEnv("OLLAMA_HOST", "http://localhost:11434"),
+		LlamaCppHost:          getEnv("LLAMACPP_HOST", "http://localhost:8080"),
+		OpenAIBaseURL:         getEnv("OPENAI_BASE_URL", "http://localhost:1234/v1"),
+		OpenAIModel:           getEnv("OPENAI_MODEL", ""),
+		OpenAIAPIKey:          getEnv("OPENAI_API_KEY", ""),
+		LLMTemperature:        getEnvFloat(envKey("LLM_TEMPERATURE", "OLLAMA_TEMPERATURE"), 0.8),
+		LLMTopP:               getEnvFloat(envKey("LLM_TOP_P", "OLLAMA_TOP_P"), 0.9),
+		LLMSeed:               getEnvInt(envKey("LLM_SEED", "OLLAMA_SEED"), 0),
+		LLMMaxTokens:          getEnvInt(envKey("LLM_MAX_TOKENS", "OLLAMA_NUM_PREDICT"), 512),
+		ScriptMaxAttempts:     getEnvInt("SCRIPT_MAX_ATTEMPTS", 3),
+		ScriptMinWords:        getEnvInt("SCRIPT_MIN_WORDS", 140),
+		ScriptMaxWords:        getEnvInt("SCRIPT_MAX_WORDS", 160),
+		ScriptMaxSeconds:      getEnvInt("SCRIPT_MAX_SECONDS", 60),
+		ScriptWPM:             getEnvInt("SCRIPT_WPM", 0),
+		PromptDir:             getEnv("PROMPT_DIR", "prompts"),
+		PromptTemplate:        getEnv("PROMPT_TEMPLATE", "shorts"),
+		ScriptTone:            getEnv("SCRIPT_TONE", "Energetic, curious, authoritative but accessible"),
+		ScriptCTA:             getEnv("SCRIPT_CTA", "Don't forget to subscribe for more AI insights!"),
+		ScriptLanguage:        getEnv("SCRIPT_LANGUAGE", "English"),
+		TTSEngine:             getEnv("TTS_ENGINE", "coqui"),
+		TTSEngines:            getEnvList("TTS_ENGINES"),
+		TTSSentencePauseMs:    getEnvInt("TTS_SENTENCE_PAUSE_MS", 250),
+		CoquiModel:            getEnv("COQUI_MODEL", "tts_models/en/vctk/vits"),
+		CoquiSpeaker:          getEnv("COQUI_SPEAKER", "p230"),
+		CoquiLanguage:         getEnv("COQUI_LANGUAGE", ""),
+		CoquiSpeakerWav:       getEnv("COQUI_SPEAKER_WAV", ""),
+		CoquiPython:           getEnv("COQUI_PYTHON", "python3"),
+		CoquiBatch:            getEnvBool("COQUI_BATCH", true),
+		ESpeakVoice:           getEnv("ESPEAK_VOICE", "en-us"),
+		ESpeakSpeed:           getEnvInt("ESPEAK_SPEED", 160),
+		LexiconPath:           getEnv("LEXICON_PATH", "assets/lexicon.txt"),
+		PiperBinary:           getEnv("PIPER_BINARY", "piper"),
+		PiperModel:            getEnv("PIPER_MODEL", "assets/voices/en_US-lessac-medium.onnx"),
+		PiperConfig:           getEnv("PIPER_CONFIG", ""),
+		PiperSpeaker:          getEnv("PIPER_SPEAKER", ""),
+		PiperLengthScale:      getEnvFloat("PIPER_LENGTH_SCALE", 1.0),
+		PiperNoiseScale:       getEnvFloat("PIPER_NOISE_SCALE", 0.667),
+		PiperNoiseW:           getEnvFloat("PIPER_NOISE_W", 0.8),
+		Aligners:              getEnvListDefault("ALIGNERS", []string{"whisper", "aeneas", "manifest"}),
+		WhisperBinary:         getEnv("WHISPER_BINARY", "whisper-cli"),
+		WhisperModel:          getEnv("WHISPER_MODEL", "assets/models/ggml-base.en.bin"),
+		AeneasPython:          getEnv("AENEAS_PYTHON", "python3"),
+		AeneasLanguage:        getEnv("AENEAS_LANGUAGE", "eng"),
+		CaptionFormat:         getEnv("CAPTION_FORMAT", "ass"),
+		CaptionExports:        getEnvListDefault("CAPTION_EXPORTS", []string{"srt", "vtt"}),
+		CaptionFont:           getEnv("CAPTION_FONT", "Arial Black"),
+		CaptionFontSize:       getEnvInt("CAPTION_FONT_SIZE", 80),
+		CaptionOutline:        getEnvInt("CAPTION_OUTLINE", 5),
+		CaptionShadow:         getEnvInt("CAPTION_SHADOW", 2),
+		CaptionColor:          getEnv("CAPTION_COLOR", "#FFFFFF"),
+		CaptionHighlightColor: getEnv("CAPTION_HIGHLIGHT_COLOR", "#FFD400"),
+		CaptionPosition:       getEnv("CAPTION_POSITION", "bottom"),
+		CaptionMargin:         getEnvInt("CAPTION_MARGIN", 480),
+		CaptionMaxChars:       getEnvInt("CAPTION_MAX_CHARS", 18),
+		CaptionMaxLines:       getEnvInt("CAPTION_MAX_LINES", 2),
+		CaptionMinSeconds:     getEnvFloat("CAPTION_MIN_SECONDS", 0.8),
+		CaptionMaxSeconds:     getEnvFloat("CAPTION_MAX_SECONDS", 3),
+		CaptionMaxCPS:         getEnvFloat("CAPTION_MAX_CPS", 17),
+		VideoWidth:            getEnvInt("VIDEO_WIDTH", 1080),
+		VideoHeight:           getEnvInt("VIDEO_HEIGHT", 1920),
+		VideoCRF:              getEnvInt("VIDEO_CRF", 18),
+		VideoPreset:           getEnv("VIDEO_PRESET", "veryfast"),
+		VideoFPS:              getEnvInt("VIDEO_FPS", 30),
+		VideoMaxRate:          getEnv("VIDEO_MAXRATE", ""),
+		AudioBitrate:          getEnv("AUDIO_BITRATE", "192k"),
+		LoudnessTarget:        getEnvFloat("LOUDNESS_TARGET", -14),
+		LoudnessTruePeak:      getEnvFloat("LOUDNESS_TRUE_PEAK", -1),
+		LoudnessRange:         getEnvFloat("LOUDNESS_RANGE", 11),
+		RenderMultiPass:       getEnvBool("RENDER_MULTIPASS", false),
+		OutroPadding:          getEnvFloat("OUTRO_PADDING", 1.5),
+		Transitions:           getEnvListDefault("TRANSITIONS", []string{"fade", "slideleft", "wipeleft", "zoomin", "circleopen"}),
+		TransitionDuration:    getEnvFloat("TRANSITION_DURATION", 0.5),
+		BackgroundSeed:        int64(getEnvInt("BACKGROUND_SEED", 0)),
+		AssetDir:              getEnv("ASSET_DIR", "assets/images"),
+		AssetIndexPath:        getEnv("ASSET_INDEX", "assets/index.json"),
+		AssetRecentRuns:       getEnvInt("ASSET_RECENT_RUNS", 3),
+		AssetVideos:           getEnvBool("ASSET_VIDEOS", true),
+		KeywordMapPath:        getEnv("KEYWORD_MAP", "assets/keywords.txt"),
+		VisualKeywords:        getEnv("VISUAL_KEYWORDS", "llm"),
+		BackgroundMinSeconds:  getEnvFloat("BACKGROUND_MIN_SECONDS", 2.5),
+		MusicLevelDB:          getEnvFloat("MUSIC_LEVEL_DB", -12),
+		MusicFadeIn:           getEnvFloat("MUSIC_FADE_IN", 1.5),
+		MusicFadeOut:          getEnvFloat("MUSIC_FADE_OUT", 2.5),
+		DuckThreshold:         getEnvFloat("DUCK_THRESHOLD", 0.05),
+		DuckRatio:             getEnvFloat("DUCK_RATIO", 8),
+		DuckAttackMs:          getEnvFloat("DUCK_ATTACK_MS", 20),
+		DuckReleaseMs:         getEnvFloat("DUCK_RELEASE_MS", 400),
+		LogoMargin:            getEnvInt("LOGO_MARGIN", 40),
+		ChannelName:           getEnv("CHANNEL_NAME", "AI Unboxed by UnboxGio"),
+		ChannelDescription:    getEnv("CHANNEL_DESCRIPTION", "a cutting-edge tech channel focused on AI innovations"),
+		MetadataFooter:        getEnv("METADATA_FOOTER", "🔔 Subscribe to {channel} for more AI insights!"),
 	}
 }
 
@@ -58,4 +227,49 @@ func getEnvInt(key string, defaultValue int) int {
 		}
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// envKey returns key, or the deprecated name it replaced when only that one
+// is set, so older .env files keep working
+func envKey(key, deprecated string) string {
+	if os.Getenv(key) == "" && os.Getenv(deprecated) != "" {
+		return deprecated
+	}
+	return key
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList splits a comma separated value, returning nil when unset
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvListDefault(key string, defaultValue []string) []string {
+	if values := getEnvList(key); len(values) > 0 {
+		return values
+	}
+	return defaultValue
+}
@@ -0,0 +1,98 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// statusError is returned when a server answers with a non-200 status
+type statusError struct {
+	backend    string
+	path       string
+	statusCode int
+	message    string
+}
+
+func (e *statusError) Error() string {
+	if e.message != "" {
+		return fmt.Sprintf("%s %s returned %d: %s", e.backend, e.path, e.statusCode, e.message)
+	}
+	return fmt.Sprintf("%s %s returned %d", e.backend, e.path, e.statusCode)
+}
+
+// httpBackend holds what every HTTP provider needs to make JSON calls
+type httpBackend struct {
+	name       string
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+func (b *httpBackend) post(ctx context.Context, path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+	return b.do(ctx, http.MethodPost, path, bytes.NewReader(payload), out)
+}
+
+func (b *httpBackend) get(ctx context.Context, path string, out interface{}) error {
+	return b.do(ctx, http.MethodGet, path, nil, out)
+}
+
+func (b *httpBackend) do(ctx context.Context, method, path string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, b.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if b.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+b.apiKey)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", b.name, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &statusError{backend: b.name, path: path, statusCode: resp.StatusCode, message: errorMessage(data)}
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// errorMessage understands both {"error": "..."} and {"error": {"message": "..."}}
+func errorMessage(data []byte) string {
+	var flat struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(data, &flat) == nil && flat.Error != "" {
+		return flat.Error
+	}
+
+	var nested struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(data, &nested) == nil {
+		return nested.Error.Message
+	}
+	return ""
+}
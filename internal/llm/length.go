@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LengthLimits bounds how long a script may be when spoken
+type LengthLimits struct {
+	MinWords       int
+	MaxWords       int
+	MaxDuration    time.Duration
+	WordsPerMinute int
+}
+
+// LengthError reports a script that is outside the configured limits
+type LengthError struct {
+	Words     int
+	Estimated time.Duration
+	Limits    LengthLimits
+}
+
+func (e *LengthError) Error() string {
+	return fmt.Sprintf("script has %d words (~%.0fs spoken), want %d-%d words and at most %.0fs",
+		e.Words, e.Estimated.Seconds(), e.Limits.MinWords, e.Limits.MaxWords, e.Limits.MaxDuration.Seconds())
+}
+
+// TooLong reports whether the script needs shortening rather than expanding
+func (e *LengthError) TooLong() bool {
+	return e.Words > e.Limits.MaxWords || (e.Limits.MaxDuration > 0 && e.Estimated > e.Limits.MaxDuration)
+}
+
+// CountWords counts spoken words, ignoring stray punctuation tokens
+func CountWords(text string) int {
+	count := 0
+	for _, field := range strings.Fields(text) {
+		if strings.IndexFunc(field, isWordRune) >= 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// EstimateDuration converts a word count into speaking time
+func EstimateDuration(words, wordsPerMinute int) time.Duration {
+	if wordsPerMinute <= 0 {
+		return 0
+	}
+	return time.Duration(words) * time.Minute / time.Duration(wordsPerMinute)
+}
+
+// Check returns a *LengthError when the script is outside the limits
+func (l LengthLimits) Check(script *Script) error {
+	words := CountWords(script.Text())
+	estimated := EstimateDuration(words, l.WordsPerMinute)
+
+	tooShort := l.MinWords > 0 && words < l.MinWords
+	tooLong := (l.MaxWords > 0 && words > l.MaxWords) || (l.MaxDuration > 0 && estimated > l.MaxDuration)
+	if tooShort || tooLong {
+		return &LengthError{Words: words, Estimated: estimated, Limits: l}
+	}
+	return nil
+}
+
+func isWordRune(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 127
+}
+
+func rewritePrompt(err *LengthError) string {
+	action := "Expand"
+	if err.TooLong() {
+		action = "Shorten"
+	}
+	return fmt.Sprintf(`Your script has %d words, which takes about %.0f seconds to narrate. %s it to between %d and %d words in total (hook, beats and cta combined) so it fits in %.0f seconds.
+
+Keep the same topic, tone and JSON structure. Return only the JSON.`,
+		err.Words, err.Estimated.Seconds(), action, err.Limits.MinWords, err.Limits.MaxWords, err.Limits.MaxDuration.Seconds())
+}
@@ -0,0 +1,55 @@
+package llm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"Hello world", 2},
+		{"Wait - what?! A.I. is here.", 5},
+		{"GPT-4o costs $20 ... per month", 5},
+	}
+
+	for _, test := range tests {
+		if got := CountWords(test.input); got != test.want {
+			t.Errorf("CountWords(%q) = %d, want %d", test.input, got, test.want)
+		}
+	}
+}
+
+func TestEstimateDuration(t *testing.T) {
+	if got := EstimateDuration(160, 160); got != time.Minute {
+		t.Errorf("EstimateDuration(160, 160) = %v, want 1m", got)
+	}
+	if got := EstimateDuration(80, 0); got != 0 {
+		t.Errorf("EstimateDuration with no rate = %v, want 0", got)
+	}
+}
+
+func TestLengthLimits_Check(t *testing.T) {
+	limits := LengthLimits{MinWords: 3, MaxWords: 6, MaxDuration: 2 * time.Second, WordsPerMinute: 120}
+	script := func(beat string) *Script {
+		return &Script{Hook: "Hey", Beats: []Beat{{Text: beat}}, CTA: "Bye"}
+	}
+
+	if err := limits.Check(script("one two")); err != nil {
+		t.Errorf("4 words should pass: %v", err)
+	}
+
+	err := limits.Check(script(""))
+	if lengthErr, ok := err.(*LengthError); !ok || lengthErr.TooLong() {
+		t.Errorf("2 words should be too short, got %v", err)
+	}
+
+	// 6 words is within MaxWords but takes 3s at 120 wpm
+	err = limits.Check(script("one two three four"))
+	if lengthErr, ok := err.(*LengthError); !ok || !lengthErr.TooLong() {
+		t.Errorf("6 words at 120 wpm should exceed 2s, got %v", err)
+	}
+}
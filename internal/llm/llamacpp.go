@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// LlamaCppProvider talks to a llama.cpp server. Plain prompts use its native
+// /completion endpoint; chat and model listing use its OpenAI-compatible API.
+type LlamaCppProvider struct {
+	httpBackend
+	chat *OpenAIProvider
+}
+
+type llamaCppCompletionRequest struct {
+	Prompt      string      `json:"prompt"`
+	Temperature float64     `json:"temperature"`
+	TopP        float64     `json:"top_p,omitempty"`
+	Seed        int         `json:"seed,omitempty"`
+	NPredict    int         `json:"n_predict,omitempty"`
+	JSONSchema  interface{} `json:"json_schema,omitempty"`
+	Stream      bool        `json:"stream"`
+}
+
+type llamaCppCompletionResponse struct {
+	Content string `json:"content"`
+}
+
+func NewLlamaCppProvider(host string) *LlamaCppProvider {
+	host = strings.TrimRight(host, "/")
+	chat := NewOpenAIProvider(host+"/v1", "", "")
+	chat.name = "llama.cpp"
+	return &LlamaCppProvider{
+		httpBackend: httpBackend{
+			name:       "llama.cpp",
+			baseURL:    host,
+			httpClient: &http.Client{},
+		},
+		chat: chat,
+	}
+}
+
+func (p *LlamaCppProvider) Name() string {
+	return "llamacpp"
+}
+
+// Generate calls /completion with streaming disabled
+func (p *LlamaCppProvider) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	prompt := req.Prompt
+	if req.System != "" {
+		prompt = req.System + "\n\n" + prompt
+	}
+
+	body := llamaCppCompletionRequest{
+		Prompt:      prompt,
+		Temperature: req.Options.Temperature,
+		TopP:        req.Options.TopP,
+		Seed:        req.Options.Seed,
+		NPredict:    req.Options.MaxTokens,
+	}
+	if req.JSON {
+		body.JSONSchema = map[string]string{"type": "object"}
+	}
+
+	var resp llamaCppCompletionResponse
+	if err := p.post(ctx, "/completion", body, &resp); err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+func (p *LlamaCppProvider) Chat(ctx context.Context, req ChatRequest) (string, error) {
+	return p.chat.Chat(ctx, req)
+}
+
+func (p *LlamaCppProvider) ListModels(ctx context.Context) ([]string, error) {
+	return p.chat.ListModels(ctx)
+}
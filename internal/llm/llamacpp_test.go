@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLlamaCppProvider_Generate(t *testing.T) {
+	var last llamaCppCompletionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/completion" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&last); err != nil {
+			t.Errorf("decode completion request: %v", err)
+		}
+		w.Write([]byte(`{"content":"{\"ok\":true}","stop":true}`))
+	}))
+	defer srv.Close()
+
+	reply, err := NewLlamaCppProvider(srv.URL).Generate(context.Background(), GenerateRequest{
+		System:  "You write scripts.",
+		Prompt:  "Write one.",
+		JSON:    true,
+		Options: Options{Temperature: 0.5, MaxTokens: 256},
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if reply != `{"ok":true}` {
+		t.Errorf("reply = %q", reply)
+	}
+	if last.Prompt != "You write scripts.\n\nWrite one." || last.NPredict != 256 || last.Temperature != 0.5 {
+		t.Errorf("unexpected request: %+v", last)
+	}
+	if last.JSONSchema == nil {
+		t.Error("JSON mode should send a json_schema")
+	}
+}
+
+func TestLlamaCppProvider_ChatUsesOpenAIEndpoint(t *testing.T) {
+	var last openAIChatRequest
+	var auth string
+	srv := newFakeOpenAI(t, "pong", &last, &auth)
+
+	reply, err := NewLlamaCppProvider(srv.URL).Chat(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "user", Content: "ping"}},
+	})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if reply != "pong" || len(last.Messages) != 1 {
+		t.Errorf("reply = %q, request = %+v", reply, last)
+	}
+}
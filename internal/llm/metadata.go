@@ -0,0 +1,182 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// YouTube upload limits
+const (
+	maxTitleLength       = 100
+	maxDescriptionLength = 5000
+	maxTagsLength        = 500
+	maxHashtags          = 15
+)
+
+// Metadata is what we paste into the YouTube upload form
+type Metadata struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Hashtags    []string `json:"hashtags"`
+}
+
+// GenerateMetadata asks the model for upload metadata and enforces YouTube's limits
+func (s *Service) GenerateMetadata(ctx context.Context, topic string, script *Script) (*Metadata, error) {
+	s.logger.Info("Generating upload metadata")
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	messages := []Message{{Role: "user", Content: s.buildMetadataPrompt(topic, script)}}
+	attempts := s.config.ScriptMaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		reply, err := s.provider.Chat(ctx, ChatRequest{
+			Messages: messages,
+			JSON:     true,
+			Options:  s.options(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s generation failed: %w", s.provider.Name(), err)
+		}
+
+		var meta Metadata
+		if err = decodeJSONObject(reply, &meta); err == nil {
+			if meta.Title == "" {
+				meta.Title = script.Title
+			}
+			if strings.TrimSpace(meta.Description) == "" {
+				err = errors.New("description is empty")
+			} else {
+				meta.enforceLimits(s.footer())
+				s.logger.Success("Metadata generated: %q (%d tags)", meta.Title, len(meta.Tags))
+				return &meta, nil
+			}
+		}
+
+		lastErr = err
+		s.logger.Warning("Metadata attempt %d/%d failed: %v", attempt, attempts, err)
+		messages = append(messages,
+			Message{Role: "assistant", Content: reply},
+			Message{Role: "user", Content: fmt.Sprintf(`Your previous reply could not be used: %v
+
+Return a single JSON object with "title", "description", "tags" and "hashtags". Return only the JSON.`, err)},
+		)
+	}
+
+	return nil, fmt.Errorf("no valid metadata after %d attempts: %w", attempts, lastErr)
+}
+
+func (s *Service) footer() string {
+	footer := strings.ReplaceAll(s.config.MetadataFooter, `\n`, "\n")
+	return strings.ReplaceAll(footer, "{channel}", s.config.ChannelName)
+}
+
+func (s *Service) buildMetadataPrompt(topic string, script *Script) string {
+	return fmt.Sprintf(`You write YouTube Shorts upload metadata for "%s".
+
+TOPIC: %s
+VIDEO TITLE IDEA: %s
+NARRATION: %s
+
+REQUIREMENTS:
+- title: catchy, under 70 characters, no hashtags
+- description: 2-3 sentences summarising the video, written in %s, no hashtags
+- tags: 10-15 search keywords or short phrases, without "#"
+- hashtags: 3-5 hashtags relevant to the topic
+
+Return a single JSON object and nothing else:
+{"title": "...", "description": "...", "tags": ["..."], "hashtags": ["#..."]}`,
+		s.config.ChannelName, topic, script.Title, script.Text(), s.config.ScriptLanguage)
+}
+
+// enforceLimits trims every field to YouTube's limits and appends the footer
+// and hashtags to the description
+func (m *Metadata) enforceLimits(footer string) {
+	m.Title = truncateWords(strings.TrimSpace(m.Title), maxTitleLength)
+	m.Hashtags = normalizeHashtags(m.Hashtags)
+	m.Tags = normalizeTags(m.Tags)
+
+	var tail []string
+	if footer = strings.TrimSpace(footer); footer != "" {
+		tail = append(tail, footer)
+	}
+	tail = append(tail, strings.Join(m.Hashtags, " "))
+	suffix := "\n\n" + strings.Join(tail, "\n\n")
+
+	body := truncateWords(strings.TrimSpace(m.Description), maxDescriptionLength-utf8.RuneCountInString(suffix))
+	m.Description = body + suffix
+}
+
+// normalizeHashtags strips spaces, adds "#", dedupes and always leads with #Shorts
+func normalizeHashtags(hashtags []string) []string {
+	result := []string{"#Shorts"}
+	seen := map[string]bool{"#shorts": true}
+
+	for _, tag := range hashtags {
+		tag = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+				return r
+			}
+			return -1
+		}, tag)
+		if tag == "" {
+			continue
+		}
+		tag = "#" + tag
+		if key := strings.ToLower(tag); !seen[key] && len(result) < maxHashtags {
+			seen[key] = true
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// normalizeTags dedupes tags and keeps as many as fit in the tag character budget
+func normalizeTags(tags []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	total := 0
+
+	for _, tag := range tags {
+		tag = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(tag), "#"))
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		// YouTube counts the separating comma towards the limit
+		length := utf8.RuneCountInString(tag) + 1
+		if total+length > maxTagsLength {
+			break
+		}
+		seen[key] = true
+		total += length
+		result = append(result, tag)
+	}
+	return result
+}
+
+// truncateWords cuts text to at most limit characters without splitting a word
+func truncateWords(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	if limit <= 0 {
+		return ""
+	}
+	cut := string([]rune(text)[:limit])
+	if i := strings.LastIndexAny(cut, " \n"); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(strings.TrimSpace(cut), ",;:-")
+}
@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+)
+
+func TestMetadata_EnforceLimits(t *testing.T) {
+	meta := Metadata{
+		Title:       strings.Repeat("Amazing ", 20),
+		Description: "Five tools you need.",
+		Tags:        []string{"ai tools", "#AI Tools", "", strings.Repeat("x", 480), "productivity"},
+		Hashtags:    []string{"AI", "#shorts", "#machine learning", "!!", "#AI"},
+	}
+	meta.enforceLimits("Subscribe!")
+
+	if len(meta.Title) > maxTitleLength || strings.HasSuffix(meta.Title, " ") {
+		t.Errorf("Title not truncated cleanly: %q (%d chars)", meta.Title, len(meta.Title))
+	}
+
+	wantHashtags := []string{"#Shorts", "#AI", "#machinelearning"}
+	if strings.Join(meta.Hashtags, ",") != strings.Join(wantHashtags, ",") {
+		t.Errorf("Hashtags = %v, want %v", meta.Hashtags, wantHashtags)
+	}
+
+	if len(meta.Tags) != 2 || meta.Tags[0] != "ai tools" || meta.Tags[1] != strings.Repeat("x", 480) {
+		t.Errorf("Tags = %v", meta.Tags)
+	}
+
+	want := "Five tools you need.\n\nSubscribe!\n\n#Shorts #AI #machinelearning"
+	if meta.Description != want {
+		t.Errorf("Description = %q, want %q", meta.Description, want)
+	}
+}
+
+func TestMetadata_LongDescription(t *testing.T) {
+	meta := Metadata{Title: "T", Description: strings.Repeat("word ", 2000)}
+	meta.enforceLimits("Footer")
+
+	if len(meta.Description) > maxDescriptionLength {
+		t.Errorf("Description has %d chars, limit %d", len(meta.Description), maxDescriptionLength)
+	}
+	if !strings.HasSuffix(meta.Description, "Footer\n\n#Shorts") {
+		t.Errorf("footer lost when truncating: %q", meta.Description[len(meta.Description)-40:])
+	}
+}
+
+func TestTruncateWords_CountsCharacters(t *testing.T) {
+	// Greek letters are two bytes each; the limit is in characters
+	title := strings.TrimSpace(strings.Repeat("Εργαλεία ", 11))
+	got := truncateWords(title, maxTitleLength)
+	if got != title {
+		t.Errorf("truncateWords cut a title that fits: %q", got)
+	}
+
+	got = truncateWords(strings.Repeat("λ", 150), maxTitleLength)
+	if !utf8.ValidString(got) || utf8.RuneCountInString(got) != maxTitleLength {
+		t.Errorf("truncateWords = %q (%d chars)", got, utf8.RuneCountInString(got))
+	}
+
+	tags := normalizeTags([]string{strings.Repeat("τ", 300), strings.Repeat("ε", 150)})
+	if len(tags) != 2 {
+		t.Errorf("Greek tags within the character budget were dropped: %d kept", len(tags))
+	}
+}
+
+func TestService_GenerateMetadata(t *testing.T) {
+	srv := newFakeOllama(t, `{"title":"","description":"Meet the tools.","tags":["ai"],"hashtags":["#AITools"]}`)
+	cfg := &config.Config{
+		OllamaHost:        srv.URL,
+		ChannelName:       "AI Unboxed",
+		MetadataFooter:    `Follow {channel}\nfor more`,
+		ScriptMaxAttempts: 2,
+	}
+	service := newTestService(t, cfg)
+	script := &Script{Title: "Hidden AI Tools", Hook: "Look!", Beats: []Beat{{Text: "One."}}, CTA: "Subscribe!"}
+
+	meta, err := service.GenerateMetadata(context.Background(), "AI tools", script)
+	if err != nil {
+		t.Fatalf("GenerateMetadata failed: %v", err)
+	}
+	if meta.Title != "Hidden AI Tools" {
+		t.Errorf("Title should fall back to the script title, got %q", meta.Title)
+	}
+	if !strings.Contains(meta.Description, "Follow AI Unboxed\nfor more") {
+		t.Errorf("footer not applied: %q", meta.Description)
+	}
+	if !strings.Contains(srv.lastChat.Messages[0].Content, "Look! One. Subscribe!") {
+		t.Error("prompt should include the narration")
+	}
+}
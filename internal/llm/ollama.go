@@ -1,81 +1,121 @@
 package llm
 
 import (
-	"bytes"
 	"context"
-	"fmt"
-	"os/exec"
+	"net/http"
 	"strings"
-	"time"
-
-	"github.com/g-laliotis/convertbox/internal/config"
-	"github.com/g-laliotis/convertbox/internal/logger"
 )
 
-type Service struct {
-	config *config.Config
-	logger *logger.Logger
+// OllamaProvider talks to the Ollama HTTP API
+type OllamaProvider struct {
+	httpBackend
+	model string
 }
 
-func NewService(cfg *config.Config, log *logger.Logger) *Service {
-	return &Service{
-		config: cfg,
-		logger: log,
-	}
+type ollamaOptions struct {
+	Temperature float64 `json:"temperature"`
+	TopP        float64 `json:"top_p,omitempty"`
+	Seed        int     `json:"seed,omitempty"`
+	NumPredict  int     `json:"num_predict,omitempty"`
 }
 
-func (s *Service) GenerateScript(ctx context.Context, topic string) (string, error) {
-	s.logger.Info("Generating script for topic: %s", topic)
+type ollamaGenerateRequest struct {
+	Model   string        `json:"model"`
+	Prompt  string        `json:"prompt"`
+	System  string        `json:"system,omitempty"`
+	Format  string        `json:"format,omitempty"`
+	Stream  bool          `json:"stream"`
+	Options ollamaOptions `json:"options"`
+}
 
-	prompt := s.buildPrompt(topic)
-	
-	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
-	defer cancel()
+type ollamaGenerateResponse struct {
+	Response string `json:"response"`
+	Done     bool   `json:"done"`
+}
 
-	cmd := exec.CommandContext(ctx, "ollama", "run", s.config.OllamaModel, prompt)
-	var out, errOut bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errOut
+type ollamaChatRequest struct {
+	Model    string        `json:"model"`
+	Messages []Message     `json:"messages"`
+	Format   string        `json:"format,omitempty"`
+	Stream   bool          `json:"stream"`
+	Options  ollamaOptions `json:"options"`
+}
 
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("ollama execution failed: %w\nStderr: %s", err, errOut.String())
-	}
+type ollamaChatResponse struct {
+	Message Message `json:"message"`
+	Done    bool    `json:"done"`
+}
 
-	script := strings.TrimSpace(out.String())
-	if script == "" {
-		return "", fmt.Errorf("ollama returned empty response")
+func NewOllamaProvider(host, model string) *OllamaProvider {
+	return &OllamaProvider{
+		httpBackend: httpBackend{
+			name:       "ollama",
+			baseURL:    strings.TrimRight(host, "/"),
+			httpClient: &http.Client{},
+		},
+		model: model,
 	}
+}
 
-	s.logger.Success("Script generated successfully (%d characters)", len(script))
-	return script, nil
+func (p *OllamaProvider) Name() string {
+	return "ollama"
 }
 
-func (s *Service) buildPrompt(topic string) string {
-	return fmt.Sprintf(`You are a professional YouTube script writer for "%s", a cutting-edge tech channel focused on AI innovations.
+// Generate calls /api/generate with streaming disabled
+func (p *OllamaProvider) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	var resp ollamaGenerateResponse
+	err := p.post(ctx, "/api/generate", ollamaGenerateRequest{
+		Model:   p.model,
+		Prompt:  req.Prompt,
+		System:  req.System,
+		Format:  ollamaFormat(req.JSON),
+		Options: toOllamaOptions(req.Options),
+	}, &resp)
+	return resp.Response, err
+}
 
-TOPIC: %s
+// Chat calls /api/chat with streaming disabled
+func (p *OllamaProvider) Chat(ctx context.Context, req ChatRequest) (string, error) {
+	var resp ollamaChatResponse
+	err := p.post(ctx, "/api/chat", ollamaChatRequest{
+		Model:    p.model,
+		Messages: req.Messages,
+		Format:   ollamaFormat(req.JSON),
+		Options:  toOllamaOptions(req.Options),
+	}, &resp)
+	return resp.Message.Content, err
+}
 
-REQUIREMENTS:
-- Write EXACTLY 140-160 words for ~60 seconds of speech
-- Structure: Hook (5s) → Main Content (50s) → CTA (5s)
-- Tone: Energetic, curious, authoritative but accessible
-- Use short, punchy sentences with natural pauses
-- Include specific numbers, facts, or examples when possible
-- End with "Don't forget to subscribe for more AI insights!"
+// ListModels returns the locally pulled models from /api/tags
+func (p *OllamaProvider) ListModels(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := p.get(ctx, "/api/tags", &resp); err != nil {
+		return nil, err
+	}
 
-STYLE GUIDELINES:
-- Start with an attention-grabbing question or bold statement
-- Use "you" to directly address viewers
-- Avoid technical jargon - explain complex concepts simply
-- Create urgency and excitement about AI developments
-- Include transition phrases like "But here's the thing..." or "What's even crazier..."
+	var models []string
+	for _, model := range resp.Models {
+		models = append(models, model.Name)
+	}
+	return models, nil
+}
 
-IMPORTANT: Return ONLY the actual script text that will be spoken. Do NOT include:
-- Title headers
-- Section labels like "(Hook)" or "(Main Content)" or "(CTA)"
-- Any formatting or commentary
-- Just the pure spoken script text
+func ollamaFormat(json bool) string {
+	if json {
+		return "json"
+	}
+	return ""
+}
 
-OUTPUT: Return ONLY the script text, no additional formatting or commentary.`, 
-		s.config.ChannelName, topic)
-}
\ No newline at end of file
+func toOllamaOptions(opts Options) ollamaOptions {
+	return ollamaOptions{
+		Temperature: opts.Temperature,
+		TopP:        opts.TopP,
+		Seed:        opts.Seed,
+		NumPredict:  opts.MaxTokens,
+	}
+}
@@ -0,0 +1,138 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeOllama stands in for a local Ollama server and records the last request
+type fakeOllama struct {
+	*httptest.Server
+	lastGenerate ollamaGenerateRequest
+	lastChat     ollamaChatRequest
+	reply        string
+	replies      []string
+	chatCalls    int
+}
+
+func newFakeOllama(t *testing.T, reply string) *fakeOllama {
+	f := &fakeOllama{reply: reply}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/generate", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&f.lastGenerate); err != nil {
+			t.Errorf("decode generate request: %v", err)
+		}
+		json.NewEncoder(w).Encode(ollamaGenerateResponse{Response: f.reply, Done: true})
+	})
+	mux.HandleFunc("/api/chat", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&f.lastChat); err != nil {
+			t.Errorf("decode chat request: %v", err)
+		}
+		reply := f.reply
+		if f.chatCalls < len(f.replies) {
+			reply = f.replies[f.chatCalls]
+		}
+		f.chatCalls++
+		json.NewEncoder(w).Encode(ollamaChatResponse{
+			Message: Message{Role: "assistant", Content: reply},
+			Done:    true,
+		})
+	})
+	mux.HandleFunc("/api/tags", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"models":[{"name":"mistral:latest"},{"name":"llama3:8b"}]}`))
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func TestOllamaProvider_Generate(t *testing.T) {
+	srv := newFakeOllama(t, "hello there")
+	provider := NewOllamaProvider(srv.URL+"/", "mistral")
+
+	reply, err := provider.Generate(context.Background(), GenerateRequest{
+		Prompt:  "say hi",
+		JSON:    true,
+		Options: Options{Temperature: 0.2, TopP: 0.5, Seed: 42, MaxTokens: 64},
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if reply != "hello there" {
+		t.Errorf("reply = %q, want %q", reply, "hello there")
+	}
+	if srv.lastGenerate.Stream || srv.lastGenerate.Format != "json" || srv.lastGenerate.Model != "mistral" {
+		t.Errorf("unexpected request: %+v", srv.lastGenerate)
+	}
+	if got := srv.lastGenerate.Options; got.Seed != 42 || got.NumPredict != 64 || got.TopP != 0.5 {
+		t.Errorf("Options not forwarded: %+v", got)
+	}
+}
+
+func TestOllamaProvider_Chat(t *testing.T) {
+	srv := newFakeOllama(t, "pong")
+	provider := NewOllamaProvider(srv.URL, "mistral")
+
+	reply, err := provider.Chat(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "user", Content: "ping"}},
+	})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if reply != "pong" {
+		t.Errorf("reply = %q, want %q", reply, "pong")
+	}
+	if len(srv.lastChat.Messages) != 1 || srv.lastChat.Messages[0].Content != "ping" {
+		t.Errorf("Messages not forwarded: %+v", srv.lastChat.Messages)
+	}
+}
+
+func TestOllamaProvider_ListModels(t *testing.T) {
+	srv := newFakeOllama(t, "")
+
+	models, err := NewOllamaProvider(srv.URL, "mistral").ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels failed: %v", err)
+	}
+	if len(models) != 2 || models[0] != "mistral:latest" {
+		t.Errorf("models = %v", models)
+	}
+}
+
+func TestOllamaProvider_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"model 'nope' not found"}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewOllamaProvider(srv.URL, "nope").Generate(context.Background(), GenerateRequest{})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestOllamaProvider_ContextCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := NewOllamaProvider(srv.URL, "mistral").Generate(ctx, GenerateRequest{})
+	if err == nil {
+		t.Fatal("expected error after context cancellation")
+	}
+}
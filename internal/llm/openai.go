@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// OpenAIProvider talks to any OpenAI-compatible endpoint such as LM Studio or vLLM
+type OpenAIProvider struct {
+	httpBackend
+	model string
+
+	// noJSONMode is set once the server rejects response_format, e.g. LM Studio
+	// only accepts json_schema or text; the prompt still asks for JSON
+	noJSONMode atomic.Bool
+}
+
+type openAIResponseFormat struct {
+	Type string `json:"type"`
+}
+
+type openAIChatRequest struct {
+	Model          string                `json:"model,omitempty"`
+	Messages       []Message             `json:"messages"`
+	Temperature    float64               `json:"temperature"`
+	TopP           float64               `json:"top_p,omitempty"`
+	Seed           int                   `json:"seed,omitempty"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+	Stream         bool                  `json:"stream"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+}
+
+// NewOpenAIProvider expects a base URL that already includes the API version,
+// e.g. http://localhost:1234/v1
+func NewOpenAIProvider(baseURL, model, apiKey string) *OpenAIProvider {
+	return &OpenAIProvider{
+		httpBackend: httpBackend{
+			name:       "openai",
+			baseURL:    strings.TrimRight(baseURL, "/"),
+			apiKey:     apiKey,
+			httpClient: &http.Client{},
+		},
+		model: model,
+	}
+}
+
+func (p *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+// Generate sends the prompt as a chat so servers without /completions still work
+func (p *OpenAIProvider) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	var messages []Message
+	if req.System != "" {
+		messages = append(messages, Message{Role: "system", Content: req.System})
+	}
+	messages = append(messages, Message{Role: "user", Content: req.Prompt})
+	return p.Chat(ctx, ChatRequest{Messages: messages, JSON: req.JSON, Options: req.Options})
+}
+
+// Chat calls /chat/completions with streaming disabled
+func (p *OpenAIProvider) Chat(ctx context.Context, req ChatRequest) (string, error) {
+	body := openAIChatRequest{
+		Model:       p.model,
+		Messages:    req.Messages,
+		Temperature: req.Options.Temperature,
+		TopP:        req.Options.TopP,
+		Seed:        req.Options.Seed,
+		MaxTokens:   req.Options.MaxTokens,
+	}
+	if req.JSON && !p.noJSONMode.Load() {
+		body.ResponseFormat = &openAIResponseFormat{Type: "json_object"}
+	}
+
+	var resp openAIChatResponse
+	err := p.post(ctx, "/chat/completions", body, &resp)
+	var status *statusError
+	if err != nil && body.ResponseFormat != nil && errors.As(err, &status) && status.statusCode == http.StatusBadRequest {
+		p.noJSONMode.Store(true)
+		body.ResponseFormat = nil
+		err = p.post(ctx, "/chat/completions", body, &resp)
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", errors.New("openai response has no choices")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// ListModels returns the model IDs served at /models
+func (p *OpenAIProvider) ListModels(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := p.get(ctx, "/models", &resp); err != nil {
+		return nil, err
+	}
+
+	var models []string
+	for _, model := range resp.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
@@ -0,0 +1,145 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newFakeOpenAI(t *testing.T, reply string, last *openAIChatRequest, auth *string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		*auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(last); err != nil {
+			t.Errorf("decode chat request: %v", err)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":` + jsonString(reply) + `}}]}`))
+	})
+	mux.HandleFunc("/v1/models", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"object":"list","data":[{"id":"qwen2.5-7b-instruct"}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+func TestOpenAIProvider_Chat(t *testing.T) {
+	var last openAIChatRequest
+	var auth string
+	srv := newFakeOpenAI(t, "pong", &last, &auth)
+	provider := NewOpenAIProvider(srv.URL+"/v1/", "qwen2.5-7b-instruct", "secret")
+
+	reply, err := provider.Chat(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "user", Content: "ping"}},
+		JSON:     true,
+		Options:  Options{Temperature: 0.3, MaxTokens: 100, Seed: 7},
+	})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if reply != "pong" {
+		t.Errorf("reply = %q, want pong", reply)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if last.Model != "qwen2.5-7b-instruct" || last.MaxTokens != 100 || last.Seed != 7 || last.Stream {
+		t.Errorf("unexpected request: %+v", last)
+	}
+	if last.ResponseFormat == nil || last.ResponseFormat.Type != "json_object" {
+		t.Errorf("JSON mode not requested: %+v", last.ResponseFormat)
+	}
+}
+
+func TestOpenAIProvider_GenerateUsesSystemMessage(t *testing.T) {
+	var last openAIChatRequest
+	var auth string
+	srv := newFakeOpenAI(t, "ok", &last, &auth)
+
+	_, err := NewOpenAIProvider(srv.URL+"/v1", "", "").Generate(context.Background(), GenerateRequest{
+		System: "be brief",
+		Prompt: "hi",
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if auth != "" {
+		t.Errorf("unexpected Authorization header %q", auth)
+	}
+	if len(last.Messages) != 2 || last.Messages[0].Role != "system" || last.Messages[1].Content != "hi" {
+		t.Errorf("messages = %+v", last.Messages)
+	}
+}
+
+func TestOpenAIProvider_ListModels(t *testing.T) {
+	var last openAIChatRequest
+	var auth string
+	srv := newFakeOpenAI(t, "", &last, &auth)
+
+	models, err := NewOpenAIProvider(srv.URL+"/v1", "", "").ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels failed: %v", err)
+	}
+	if len(models) != 1 || models[0] != "qwen2.5-7b-instruct" {
+		t.Errorf("models = %v", models)
+	}
+}
+
+func TestOpenAIProvider_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"model not loaded","type":"invalid_request_error"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewOpenAIProvider(srv.URL, "", "").Chat(context.Background(), ChatRequest{})
+	if err == nil || !strings.Contains(err.Error(), "model not loaded") {
+		t.Fatalf("expected API error message, got %v", err)
+	}
+}
+
+func TestOpenAIProvider_FallsBackWithoutJSONMode(t *testing.T) {
+	var formats []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode chat request: %v", err)
+		}
+		if req.ResponseFormat != nil {
+			formats = append(formats, req.ResponseFormat.Type)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"'response_format.type' must be 'json_schema' or 'text'"}`))
+			return
+		}
+		formats = append(formats, "")
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{}"}}]}`))
+	}))
+	defer srv.Close()
+
+	provider := NewOpenAIProvider(srv.URL, "", "")
+	for i := 0; i < 2; i++ {
+		reply, err := provider.Chat(context.Background(), ChatRequest{
+			Messages: []Message{{Role: "user", Content: "ping"}},
+			JSON:     true,
+		})
+		if err != nil {
+			t.Fatalf("Chat failed: %v", err)
+		}
+		if reply != "{}" {
+			t.Errorf("reply = %q", reply)
+		}
+	}
+
+	// The rejected format is retried once as plain text, then no longer sent
+	if strings.Join(formats, ",") != "json_object,," {
+		t.Errorf("response formats sent = %q", formats)
+	}
+}
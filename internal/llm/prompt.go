@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// PromptVars are the variables available to prompt templates
+type PromptVars struct {
+	ChannelName        string
+	ChannelDescription string
+	Topic              string
+	MinWords           int
+	MaxWords           int
+	MaxSeconds         int
+	Tone               string
+	CTA                string
+	Language           string
+}
+
+// PromptTemplate is a text/template that renders the script prompt
+type PromptTemplate struct {
+	Name string
+	tmpl *template.Template
+}
+
+// LoadPromptTemplate loads <dir>/<name>.tmpl, or name itself when it is a path
+func LoadPromptTemplate(dir, name string) (*PromptTemplate, error) {
+	if name == "" {
+		return nil, fmt.Errorf("no prompt template configured")
+	}
+
+	path := name
+	if !strings.ContainsRune(name, filepath.Separator) && filepath.Ext(name) == "" {
+		path = filepath.Join(dir, name+".tmpl")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read prompt template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse prompt template %s: %w", path, err)
+	}
+	return &PromptTemplate{Name: name, tmpl: tmpl}, nil
+}
+
+// Render executes the template and appends the JSON output contract the parser relies on
+func (p *PromptTemplate) Render(vars PromptVars) (string, error) {
+	var buf bytes.Buffer
+	if err := p.tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("render prompt template %s: %w", p.Name, err)
+	}
+
+	prompt := strings.TrimSpace(buf.String())
+	if prompt == "" {
+		return "", fmt.Errorf("prompt template %s rendered empty", p.Name)
+	}
+	return prompt + "\n\n" + scriptOutputFormat, nil
+}
+
+const scriptOutputFormat = `OUTPUT FORMAT:
+Return a single JSON object and nothing else:
+{
+  "title": "short video title",
+  "hook": "spoken opening line",
+  "beats": [
+    {"text": "spoken text of this beat", "keywords": ["2-4 concrete visual keywords for the background"], "transition": "optional: fade, slideleft, wipeleft, zoomin or circleopen into this beat"}
+  ],
+  "cta": "spoken call to action"
+}
+
+Every "text", "hook" and "cta" value is read aloud verbatim, so it must contain only spoken words.`
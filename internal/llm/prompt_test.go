@@ -0,0 +1,68 @@
+package llm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPromptTemplate_Bundled(t *testing.T) {
+	vars := PromptVars{
+		ChannelName:        "Test Channel",
+		ChannelDescription: "a cooking channel for busy parents",
+		Topic:              "Robots that cook",
+		MinWords:           100,
+		MaxWords:           120,
+		MaxSeconds:         45,
+		Tone:               "Calm",
+		CTA:                "Follow for more!",
+		Language:           "Greek",
+	}
+
+	for _, name := range []string{"shorts", "listicle"} {
+		prompt, err := LoadPromptTemplate("../../prompts", name)
+		if err != nil {
+			t.Fatalf("LoadPromptTemplate(%s) failed: %v", name, err)
+		}
+		text, err := prompt.Render(vars)
+		if err != nil {
+			t.Fatalf("Render(%s) failed: %v", name, err)
+		}
+		for _, want := range []string{`"Test Channel", a cooking channel for busy parents.`, "Robots that cook", "100-120 words", "Greek", "Follow for more!", `"beats"`} {
+			if !strings.Contains(text, want) {
+				t.Errorf("%s prompt missing %q", name, want)
+			}
+		}
+		if strings.Contains(text, "AI innovations") {
+			t.Errorf("%s prompt still hardcodes the channel description", name)
+		}
+	}
+}
+
+func TestLoadPromptTemplate_Errors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	if _, err := LoadPromptTemplate(dir, write("broken.tmpl", "{{.Topic")); err == nil {
+		t.Error("expected parse error")
+	}
+
+	prompt, err := LoadPromptTemplate(dir, write("unknown.tmpl", "About {{.Subject}}"))
+	if err != nil {
+		t.Fatalf("LoadPromptTemplate failed: %v", err)
+	}
+	if _, err := prompt.Render(PromptVars{}); err == nil {
+		t.Error("expected error for unknown variable")
+	}
+
+	if _, err := LoadPromptTemplate(dir, ""); err == nil {
+		t.Error("expected error for empty template name")
+	}
+}
@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+)
+
+// Provider is a text generation backend
+type Provider interface {
+	Name() string
+	Generate(ctx context.Context, req GenerateRequest) (string, error)
+	Chat(ctx context.Context, req ChatRequest) (string, error)
+	ListModels(ctx context.Context) ([]string, error)
+}
+
+// Options holds the sampling parameters forwarded to the provider
+type Options struct {
+	Temperature float64
+	TopP        float64
+	Seed        int
+	MaxTokens   int
+}
+
+// Message is a single chat turn
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// GenerateRequest is a single-prompt completion
+type GenerateRequest struct {
+	Prompt  string
+	System  string
+	JSON    bool
+	Options Options
+}
+
+// ChatRequest is a multi-turn completion
+type ChatRequest struct {
+	Messages []Message
+	JSON     bool
+	Options  Options
+}
+
+// NewProvider returns the backend selected by LLM_PROVIDER
+func NewProvider(cfg *config.Config) (Provider, error) {
+	switch strings.ToLower(cfg.LLMProvider) {
+	case "", "ollama":
+		return NewOllamaProvider(cfg.OllamaHost, cfg.OllamaModel), nil
+	case "llamacpp", "llama.cpp":
+		return NewLlamaCppProvider(cfg.LlamaCppHost), nil
+	case "openai":
+		return NewOpenAIProvider(cfg.OpenAIBaseURL, cfg.OpenAIModel, cfg.OpenAIAPIKey), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q (want ollama, llamacpp or openai)", cfg.LLMProvider)
+	}
+}
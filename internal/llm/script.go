@@ -0,0 +1,133 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type SegmentKind string
+
+const (
+	SegmentHook SegmentKind = "hook"
+	SegmentBeat SegmentKind = "beat"
+	SegmentCTA  SegmentKind = "cta"
+)
+
+// Beat is one point of the main content with the visuals it suggests
+type Beat struct {
+	Text       string   `json:"text"`
+	Keywords   []string `json:"keywords"`
+	Transition string   `json:"transition,omitempty"` // xfade transition into the beat's background
+}
+
+// Script is the structured output requested from the LLM
+type Script struct {
+	Title string `json:"title"`
+	Hook  string `json:"hook"`
+	Beats []Beat `json:"beats"`
+	CTA   string `json:"cta"`
+}
+
+// Segment is a spoken part of the script in playback order
+type Segment struct {
+	Kind       SegmentKind
+	Text       string
+	Keywords   []string
+	Transition string
+}
+
+// ParseScript extracts the JSON object from a model reply and validates it
+func ParseScript(raw string) (*Script, error) {
+	var script Script
+	if err := decodeJSONObject(raw, &script); err != nil {
+		return nil, err
+	}
+	script.normalize()
+
+	if err := script.Validate(); err != nil {
+		return nil, err
+	}
+	return &script, nil
+}
+
+// Validate checks that every section the pipeline relies on is present
+func (s *Script) Validate() error {
+	var problems []string
+	if s.Title == "" {
+		problems = append(problems, "title is empty")
+	}
+	if s.Hook == "" {
+		problems = append(problems, "hook is empty")
+	}
+	if len(s.Beats) == 0 {
+		problems = append(problems, "beats list is empty")
+	}
+	for i, beat := range s.Beats {
+		if beat.Text == "" {
+			problems = append(problems, fmt.Sprintf("beat %d has no text", i+1))
+		}
+	}
+	if s.CTA == "" {
+		problems = append(problems, "cta is empty")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid script: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Segments returns hook, beats and CTA in the order they are spoken
+func (s *Script) Segments() []Segment {
+	segments := []Segment{{Kind: SegmentHook, Text: s.Hook}}
+	for _, beat := range s.Beats {
+		segments = append(segments, Segment{Kind: SegmentBeat, Text: beat.Text, Keywords: beat.Keywords, Transition: beat.Transition})
+	}
+	return append(segments, Segment{Kind: SegmentCTA, Text: s.CTA})
+}
+
+// Text returns the full narration as a single string
+func (s *Script) Text() string {
+	var parts []string
+	for _, segment := range s.Segments() {
+		parts = append(parts, segment.Text)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (s *Script) normalize() {
+	s.Title = strings.TrimSpace(s.Title)
+	s.Hook = strings.TrimSpace(s.Hook)
+	s.CTA = strings.TrimSpace(s.CTA)
+	for i := range s.Beats {
+		s.Beats[i].Text = strings.TrimSpace(s.Beats[i].Text)
+		s.Beats[i].Transition = strings.ToLower(strings.TrimSpace(s.Beats[i].Transition))
+		var keywords []string
+		for _, keyword := range s.Beats[i].Keywords {
+			if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+				keywords = append(keywords, keyword)
+			}
+		}
+		s.Beats[i].Keywords = keywords
+	}
+}
+
+// decodeJSONObject decodes the first JSON object in a model reply, skipping
+// any prose or code fences around it
+func decodeJSONObject(raw string, out interface{}) error {
+	start := strings.Index(raw, "{")
+	if start < 0 {
+		return errors.New("no JSON object found in response")
+	}
+	body := raw[start:]
+	if end := strings.LastIndex(body, "}"); end >= 0 {
+		body = body[:end+1]
+	}
+
+	if err := json.Unmarshal([]byte(body), out); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
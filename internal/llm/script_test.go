@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseScript(t *testing.T) {
+	raw := "Sure! Here is your script:\n```json\n" + `{
+		"title": " Hidden A.I. Sites ",
+		"hook": "Stop scrolling!",
+		"beats": [
+			{"text": "First, this site writes code.", "keywords": [" Code ", ""]},
+			{"text": "Second, this one paints.", "keywords": ["art"], "transition": " SlideLeft "}
+		],
+		"cta": "Subscribe for more!"
+	}` + "\n```"
+
+	script, err := ParseScript(raw)
+	if err != nil {
+		t.Fatalf("ParseScript failed: %v", err)
+	}
+	if script.Title != "Hidden A.I. Sites" {
+		t.Errorf("Title = %q", script.Title)
+	}
+	if got := script.Beats[0].Keywords; len(got) != 1 || got[0] != "code" {
+		t.Errorf("Keywords = %v, want [code]", got)
+	}
+
+	segments := script.Segments()
+	if len(segments) != 4 || segments[0].Kind != SegmentHook || segments[3].Kind != SegmentCTA {
+		t.Fatalf("unexpected segments: %+v", segments)
+	}
+	if segments[1].Transition != "" || segments[2].Transition != "slideleft" {
+		t.Errorf("beat transitions = %q, %q", segments[1].Transition, segments[2].Transition)
+	}
+	if text := script.Text(); !strings.HasPrefix(text, "Stop scrolling! First") || !strings.HasSuffix(text, "Subscribe for more!") {
+		t.Errorf("Text = %q", text)
+	}
+}
+
+func TestParseScript_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"no json", "I cannot help with that", "no JSON object"},
+		{"truncated", `{"title": "x", "hook": `, "invalid JSON"},
+		{"missing beats", `{"title": "x", "hook": "y", "cta": "z"}`, "beats list is empty"},
+		{"empty beat", `{"title": "x", "hook": "y", "beats": [{"text": " "}], "cta": "z"}`, "beat 1 has no text"},
+	}
+
+	for _, test := range tests {
+		_, err := ParseScript(test.raw)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: ParseScript error = %v, want %q", test.name, err, test.want)
+		}
+	}
+}
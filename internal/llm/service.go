@@ -0,0 +1,193 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+	"github.com/g-laliotis/convertbox/internal/logger"
+)
+
+type Service struct {
+	config   *config.Config
+	logger   *logger.Logger
+	provider Provider
+	prompt   *PromptTemplate
+}
+
+// NewService selects the provider and loads the prompt template, rendering it
+// once so template errors surface before the pipeline starts
+func NewService(cfg *config.Config, log *logger.Logger) (*Service, error) {
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := LoadPromptTemplate(cfg.PromptDir, cfg.PromptTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Service{
+		config:   cfg,
+		logger:   log,
+		provider: provider,
+		prompt:   prompt,
+	}
+	if _, err := s.buildPrompt("Example topic"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ListModels returns the models available from the configured provider
+func (s *Service) ListModels(ctx context.Context) ([]string, error) {
+	return s.provider.ListModels(ctx)
+}
+
+// Attempt records one model reply and why it was rejected
+type Attempt struct {
+	Number           int     `json:"attempt"`
+	Response         string  `json:"response"`
+	Words            int     `json:"words,omitempty"`
+	EstimatedSeconds float64 `json:"estimated_seconds,omitempty"`
+	Error            string  `json:"error,omitempty"`
+}
+
+// GenerateScript asks the model for a structured script, repairing malformed
+// replies and requesting rewrites until the script fits the length limits.
+// The returned attempts describe every reply, including the accepted one.
+func (s *Service) GenerateScript(ctx context.Context, topic string) (*Script, []Attempt, error) {
+	s.logger.Info("Generating script for topic: %s", topic)
+
+	limits := s.lengthLimits()
+	prompt, err := s.buildPrompt(topic)
+	if err != nil {
+		return nil, nil, err
+	}
+	messages := []Message{{Role: "user", Content: prompt}}
+	attempts := s.config.ScriptMaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var history []Attempt
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		reply, err := s.chat(ctx, messages)
+		if err != nil {
+			return nil, history, fmt.Errorf("%s generation failed: %w", s.provider.Name(), err)
+		}
+
+		record := Attempt{Number: attempt, Response: reply}
+
+		script, err := ParseScript(reply)
+		if err == nil {
+			words := CountWords(script.Text())
+			record.Words = words
+			record.EstimatedSeconds = EstimateDuration(words, limits.WordsPerMinute).Seconds()
+
+			if err = limits.Check(script); err == nil {
+				history = append(history, record)
+				s.logger.Success("Script generated successfully (%d beats, %d words, ~%.0fs)", len(script.Beats), words, record.EstimatedSeconds)
+				return script, history, nil
+			}
+		}
+		followUp := repairPrompt(err)
+		var lengthErr *LengthError
+		if errors.As(err, &lengthErr) {
+			followUp = rewritePrompt(lengthErr)
+		}
+
+		record.Error = err.Error()
+		history = append(history, record)
+		lastErr = err
+		s.logger.Warning("Attempt %d/%d returned an unusable script: %v", attempt, attempts, err)
+		messages = append(messages,
+			Message{Role: "assistant", Content: reply},
+			Message{Role: "user", Content: followUp},
+		)
+	}
+
+	return nil, history, fmt.Errorf("no valid script after %d attempts: %w", attempts, lastErr)
+}
+
+// chat gives every attempt its own deadline so a slow first reply does not
+// eat into the time of the rewrites
+func (s *Service) chat(ctx context.Context, messages []Message) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	return s.provider.Chat(ctx, ChatRequest{
+		Messages: messages,
+		JSON:     true,
+		Options:  s.options(),
+	})
+}
+
+func (s *Service) lengthLimits() LengthLimits {
+	return LengthLimits{
+		MinWords:       s.config.ScriptMinWords,
+		MaxWords:       s.config.ScriptMaxWords,
+		MaxDuration:    time.Duration(s.config.ScriptMaxSeconds) * time.Second,
+		WordsPerMinute: s.wordsPerMinute(),
+	}
+}
+
+// wordsPerMinute returns SCRIPT_WPM, or the speaking rate of the first TTS
+// engine the narration will try
+func (s *Service) wordsPerMinute() int {
+	const natural = 160 // Coqui and Piper voices at their default pace
+	if s.config.ScriptWPM > 0 {
+		return s.config.ScriptWPM
+	}
+
+	engine := s.config.TTSEngine
+	if len(s.config.TTSEngines) > 0 {
+		engine = s.config.TTSEngines[0]
+	}
+	switch strings.ToLower(strings.TrimSpace(engine)) {
+	case "espeak":
+		if s.config.ESpeakSpeed > 0 {
+			return s.config.ESpeakSpeed
+		}
+	case "piper":
+		// length_scale stretches every phoneme, >1 speaks slower
+		if s.config.PiperLengthScale > 0 {
+			return int(math.Round(natural / s.config.PiperLengthScale))
+		}
+	}
+	return natural
+}
+
+func (s *Service) options() Options {
+	return Options{
+		Temperature: s.config.LLMTemperature,
+		TopP:        s.config.LLMTopP,
+		Seed:        s.config.LLMSeed,
+		MaxTokens:   s.config.LLMMaxTokens,
+	}
+}
+
+func (s *Service) buildPrompt(topic string) (string, error) {
+	return s.prompt.Render(PromptVars{
+		ChannelName:        s.config.ChannelName,
+		ChannelDescription: s.config.ChannelDescription,
+		Topic:              topic,
+		MinWords:           s.config.ScriptMinWords,
+		MaxWords:           s.config.ScriptMaxWords,
+		MaxSeconds:         s.config.ScriptMaxSeconds,
+		Tone:               s.config.ScriptTone,
+		CTA:                s.config.ScriptCTA,
+		Language:           s.config.ScriptLanguage,
+	})
+}
+
+func repairPrompt(err error) string {
+	return fmt.Sprintf(`Your previous reply could not be used: %v
+
+Return the corrected script as a single JSON object with "title", "hook", "beats" (each with "text" and "keywords") and "cta". Return only the JSON.`, err)
+}
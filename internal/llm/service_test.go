@@ -0,0 +1,166 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+	"github.com/g-laliotis/convertbox/internal/logger"
+)
+
+const validScriptJSON = `{"title":"A.I. Scripts","hook":"Did you know A.I. can write scripts?","beats":[{"text":"It drafts in seconds.","keywords":["Robot","typing"]}],"cta":"Subscribe for more!"}`
+
+func newTestService(t *testing.T, cfg *config.Config) *Service {
+	cfg.PromptDir = "../../prompts"
+	cfg.PromptTemplate = "shorts"
+	service, err := NewService(cfg, logger.New())
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+	return service
+}
+
+func TestService_GenerateScript(t *testing.T) {
+	srv := newFakeOllama(t, validScriptJSON)
+	cfg := &config.Config{OllamaHost: srv.URL, OllamaModel: "mistral", LLMTemperature: 0.7, ScriptMaxAttempts: 3}
+	service := newTestService(t, cfg)
+
+	script, _, err := service.GenerateScript(context.Background(), "AI scripts")
+	if err != nil {
+		t.Fatalf("GenerateScript failed: %v", err)
+	}
+	if script.Hook != "Did you know A.I. can write scripts?" {
+		t.Errorf("Hook = %q", script.Hook)
+	}
+	if srv.lastChat.Model != "mistral" || srv.lastChat.Format != "json" || !strings.Contains(srv.lastChat.Messages[0].Content, "AI scripts") {
+		t.Errorf("unexpected request: %+v", srv.lastChat)
+	}
+	if srv.lastChat.Options.Temperature != 0.7 {
+		t.Errorf("Temperature = %v, want 0.7", srv.lastChat.Options.Temperature)
+	}
+}
+
+func TestService_GenerateScriptRepairsMalformedJSON(t *testing.T) {
+	srv := newFakeOllama(t, validScriptJSON)
+	srv.replies = []string{`{"title": "Broken", "hook": `}
+	cfg := &config.Config{OllamaHost: srv.URL, OllamaModel: "mistral", ScriptMaxAttempts: 3}
+	service := newTestService(t, cfg)
+
+	script, _, err := service.GenerateScript(context.Background(), "AI scripts")
+	if err != nil {
+		t.Fatalf("GenerateScript failed: %v", err)
+	}
+	if srv.chatCalls != 2 {
+		t.Errorf("chat calls = %d, want 2", srv.chatCalls)
+	}
+	if len(srv.lastChat.Messages) != 3 || srv.lastChat.Messages[1].Role != "assistant" {
+		t.Errorf("repair request should replay the bad reply: %+v", srv.lastChat.Messages)
+	}
+	if script.Title != "A.I. Scripts" {
+		t.Errorf("Title = %q", script.Title)
+	}
+}
+
+func TestService_GenerateScriptGivesUp(t *testing.T) {
+	srv := newFakeOllama(t, "not json at all")
+	cfg := &config.Config{OllamaHost: srv.URL, OllamaModel: "mistral", ScriptMaxAttempts: 2}
+	service := newTestService(t, cfg)
+
+	if _, _, err := service.GenerateScript(context.Background(), "AI scripts"); err == nil {
+		t.Fatal("expected error after exhausting attempts")
+	}
+	if srv.chatCalls != 2 {
+		t.Errorf("chat calls = %d, want 2", srv.chatCalls)
+	}
+}
+
+func TestService_GenerateScriptRewritesLongScript(t *testing.T) {
+	long := `{"title":"T","hook":"Hook words here.","beats":[{"text":"` + strings.Repeat("word ", 40) + `"}],"cta":"Subscribe now!"}`
+	srv := newFakeOllama(t, validScriptJSON)
+	srv.replies = []string{long}
+	cfg := &config.Config{OllamaHost: srv.URL, OllamaModel: "mistral", ScriptMaxAttempts: 3, ScriptMinWords: 5, ScriptMaxWords: 20, ScriptMaxSeconds: 60}
+	service := newTestService(t, cfg)
+
+	_, attempts, err := service.GenerateScript(context.Background(), "AI scripts")
+	if err != nil {
+		t.Fatalf("GenerateScript failed: %v", err)
+	}
+	if len(attempts) != 2 || attempts[0].Error == "" || attempts[1].Error != "" {
+		t.Fatalf("unexpected attempt history: %+v", attempts)
+	}
+	if !strings.Contains(srv.lastChat.Messages[2].Content, "Shorten") {
+		t.Errorf("rewrite request should ask to shorten: %q", srv.lastChat.Messages[2].Content)
+	}
+}
+
+func TestService_GenerateScriptLengthError(t *testing.T) {
+	srv := newFakeOllama(t, validScriptJSON)
+	cfg := &config.Config{OllamaHost: srv.URL, OllamaModel: "mistral", ScriptMaxAttempts: 2, ScriptMinWords: 100, ScriptMaxWords: 200}
+	service := newTestService(t, cfg)
+
+	_, attempts, err := service.GenerateScript(context.Background(), "AI scripts")
+	var lengthErr *LengthError
+	if !errors.As(err, &lengthErr) {
+		t.Fatalf("expected *LengthError, got %v", err)
+	}
+	if lengthErr.TooLong() {
+		t.Error("short script reported as too long")
+	}
+	if len(attempts) != 2 {
+		t.Errorf("got %d attempts, want 2", len(attempts))
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		provider string
+		want     string
+	}{
+		{"", "ollama"},
+		{"ollama", "ollama"},
+		{"llama.cpp", "llamacpp"},
+		{"OpenAI", "openai"},
+	}
+
+	for _, test := range tests {
+		provider, err := NewProvider(&config.Config{LLMProvider: test.provider})
+		if err != nil {
+			t.Fatalf("NewProvider(%q) failed: %v", test.provider, err)
+		}
+		if provider.Name() != test.want {
+			t.Errorf("NewProvider(%q) = %s, want %s", test.provider, provider.Name(), test.want)
+		}
+	}
+
+	if _, err := NewProvider(&config.Config{LLMProvider: "gpt-cloud"}); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+func TestNewService_InvalidPrompt(t *testing.T) {
+	cfg := &config.Config{PromptDir: "../../prompts", PromptTemplate: "does-not-exist"}
+	if _, err := NewService(cfg, logger.New()); err == nil {
+		t.Fatal("expected error for missing prompt template")
+	}
+}
+
+func TestService_WordsPerMinute(t *testing.T) {
+	tests := []struct {
+		cfg  config.Config
+		want int
+	}{
+		{config.Config{TTSEngine: "coqui", ESpeakSpeed: 200}, 160},
+		{config.Config{TTSEngine: "espeak", ESpeakSpeed: 200}, 200},
+		{config.Config{TTSEngines: []string{"piper", "espeak"}, PiperLengthScale: 1.25, ESpeakSpeed: 200}, 128},
+		{config.Config{TTSEngine: "espeak", ESpeakSpeed: 200, ScriptWPM: 150}, 150},
+	}
+
+	for _, test := range tests {
+		service := &Service{config: &test.cfg}
+		if got := service.wordsPerMinute(); got != test.want {
+			t.Errorf("wordsPerMinute(%+v) = %d, want %d", test.cfg, got, test.want)
+		}
+	}
+}
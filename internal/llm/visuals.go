@@ -0,0 +1,91 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// visualsReply is the JSON the model returns for visual keyword extraction
+type visualsReply struct {
+	Visuals [][]string `json:"visuals"`
+}
+
+// ExtractVisuals asks the model for the concrete things a viewer could see
+// while each sentence is spoken, returning one keyword list per sentence
+func (s *Service) ExtractVisuals(ctx context.Context, sentences []string) ([][]string, error) {
+	if len(sentences) == 0 {
+		return nil, nil
+	}
+	s.logger.Info("Extracting visual keywords for %d sentences", len(sentences))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	messages := []Message{{Role: "user", Content: buildVisualsPrompt(sentences)}}
+	attempts := s.config.ScriptMaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		reply, err := s.provider.Chat(ctx, ChatRequest{
+			Messages: messages,
+			JSON:     true,
+			Options:  s.options(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s generation failed: %w", s.provider.Name(), err)
+		}
+
+		var parsed visualsReply
+		if err = decodeJSONObject(reply, &parsed); err == nil {
+			if len(parsed.Visuals) == len(sentences) {
+				return normalizeVisuals(parsed.Visuals), nil
+			}
+			err = fmt.Errorf("got keywords for %d sentences, want %d", len(parsed.Visuals), len(sentences))
+		}
+
+		lastErr = err
+		s.logger.Warning("Visual keyword attempt %d/%d failed: %v", attempt, attempts, err)
+		messages = append(messages,
+			Message{Role: "assistant", Content: reply},
+			Message{Role: "user", Content: fmt.Sprintf(`Your previous reply could not be used: %v
+
+Return a single JSON object with a "visuals" array holding exactly %d keyword lists, one per sentence in order. Return only the JSON.`, err, len(sentences))},
+		)
+	}
+
+	return nil, fmt.Errorf("no valid visual keywords after %d attempts: %w", attempts, lastErr)
+}
+
+func buildVisualsPrompt(sentences []string) string {
+	var numbered strings.Builder
+	for i, sentence := range sentences {
+		fmt.Fprintf(&numbered, "%d. %s\n", i+1, sentence)
+	}
+	return fmt.Sprintf(`You pick background footage for a narrated short video.
+
+For each numbered sentence, list 1-3 concrete visual concepts a viewer could see on screen while it is spoken: objects, places or scenes such as "robot", "city skyline" or "laptop". Use short lowercase nouns, not abstract ideas or the narrator's words verbatim.
+
+SENTENCES:
+%s
+Return a single JSON object and nothing else, with one list per sentence in order:
+{"visuals": [["...", "..."], ["..."]]}`, numbered.String())
+}
+
+// normalizeVisuals lowercases keywords and drops empty ones
+func normalizeVisuals(visuals [][]string) [][]string {
+	for i, keywords := range visuals {
+		var cleaned []string
+		for _, keyword := range keywords {
+			if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+				cleaned = append(cleaned, keyword)
+			}
+		}
+		visuals[i] = cleaned
+	}
+	return visuals
+}
@@ -0,0 +1,39 @@
+package llm
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+)
+
+func TestService_ExtractVisuals(t *testing.T) {
+	srv := newFakeOllama(t, "")
+	srv.replies = []string{
+		`{"visuals": [["robot"]]}`,
+		"```json\n{\"visuals\": [[\" Robot Arm \", \"\"], [\"city skyline\", \"night\"]]}\n```",
+	}
+	service := newTestService(t, &config.Config{OllamaHost: srv.URL, ScriptMaxAttempts: 2})
+
+	sentences := []string{"Robots build cars.", "Cities never sleep."}
+	visuals, err := service.ExtractVisuals(context.Background(), sentences)
+	if err != nil {
+		t.Fatalf("ExtractVisuals failed: %v", err)
+	}
+
+	want := [][]string{{"robot arm"}, {"city skyline", "night"}}
+	if !reflect.DeepEqual(visuals, want) {
+		t.Errorf("visuals = %q, want %q", visuals, want)
+	}
+	if srv.chatCalls != 2 {
+		t.Errorf("expected a retry after the short reply, got %d calls", srv.chatCalls)
+	}
+	if !strings.Contains(srv.lastChat.Messages[0].Content, "2. Cities never sleep.") {
+		t.Error("prompt should number the sentences")
+	}
+	if !strings.Contains(srv.lastChat.Messages[2].Content, "got keywords for 1 sentences, want 2") {
+		t.Errorf("retry should explain the problem: %q", srv.lastChat.Messages[2].Content)
+	}
+}
@@ -0,0 +1,157 @@
+package media
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// captionSideMargin keeps long captions off the left and right edges
+const captionSideMargin = 60
+
+// captionStyle is the look of burned-in ASS captions
+type captionStyle struct {
+	Font      string
+	Size      int
+	Outline   int
+	Shadow    int
+	Color     string // ASS &HAABBGGRR
+	Highlight string // ASS &HAABBGGRR
+	Alignment int    // numpad position: 2 bottom, 5 center, 8 top
+	Margin    int
+	Width     int
+	Height    int
+}
+
+func (s *Service) captionStyle() (captionStyle, error) {
+	color, err := assColor(s.config.CaptionColor)
+	if err != nil {
+		return captionStyle{}, fmt.Errorf("CAPTION_COLOR: %w", err)
+	}
+	highlight, err := assColor(s.config.CaptionHighlightColor)
+	if err != nil {
+		return captionStyle{}, fmt.Errorf("CAPTION_HIGHLIGHT_COLOR: %w", err)
+	}
+
+	alignment := 2
+	switch strings.ToLower(s.config.CaptionPosition) {
+	case "", "bottom":
+	case "center", "middle":
+		alignment = 5
+	case "top":
+		alignment = 8
+	default:
+		return captionStyle{}, fmt.Errorf("unknown CAPTION_POSITION %q (bottom, center or top)", s.config.CaptionPosition)
+	}
+
+	width, height := s.frameSize()
+	style := captionStyle{
+		Font:      s.config.CaptionFont,
+		Size:      s.config.CaptionFontSize,
+		Outline:   s.config.CaptionOutline,
+		Shadow:    s.config.CaptionShadow,
+		Color:     color,
+		Highlight: highlight,
+		Alignment: alignment,
+		Margin:    s.config.CaptionMargin,
+		Width:     width,
+		Height:    height,
+	}
+	if style.Font == "" {
+		style.Font = "Arial"
+	}
+	if style.Size <= 0 {
+		style.Size = 80
+	}
+	return style, nil
+}
+
+// writeASS writes styled captions in which each word is highlighted with a
+// karaoke tag while it is spoken
+func (s *Service) writeASS(w *bufio.Writer, cues []Cue) error {
+	style, err := s.captionStyle()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "[Script Info]\nScriptType: v4.00+\nPlayResX: %d\nPlayResY: %d\nWrapStyle: 0\nScaledBorderAndShadow: yes\n\n",
+		style.Width, style.Height)
+
+	// With \k tags libass fills words with the primary colour as they are
+	// spoken; the secondary colour is the not-yet-spoken text
+	w.WriteString("[V4+ Styles]\n")
+	w.WriteString("Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	fmt.Fprintf(w, "Style: Default,%s,%d,%s,%s,&H00000000,&H80000000,-1,0,0,0,100,100,0,0,1,%d,%d,%d,%d,%d,%d,1\n\n",
+		style.Font, style.Size, style.Highlight, style.Color,
+		style.Outline, style.Shadow, style.Alignment,
+		captionSideMargin, captionSideMargin, style.Margin)
+
+	w.WriteString("[Events]\n")
+	w.WriteString("Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+	for _, cue := range cues {
+		fmt.Fprintf(w, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n",
+			formatASSTime(cue.Start),
+			formatASSTime(cue.End),
+			karaokeText(cue),
+		)
+	}
+	return nil
+}
+
+// karaokeText tags each word with its duration in centiseconds, measured to
+// the start of the next word so pauses keep the previous word lit
+func karaokeText(cue Cue) string {
+	// Words at which a new line starts
+	lineStarts := map[int]bool{}
+	count := 0
+	for _, line := range cue.Lines {
+		lineStarts[count] = true
+		count += len(strings.Fields(line))
+	}
+
+	var text strings.Builder
+	for i, word := range cue.Words {
+		end := cue.End
+		if i+1 < len(cue.Words) {
+			end = cue.Words[i+1].Start
+		}
+		if i > 0 && lineStarts[i] {
+			text.WriteString(`\N`)
+		} else if i > 0 {
+			text.WriteString(" ")
+		}
+		fmt.Fprintf(&text, `{\k%d}%s`, centiseconds(end-word.Start), escapeASS(word.Text))
+	}
+	return text.String()
+}
+
+// assColor converts #RRGGBB into ASS's &HAABBGGRR notation
+func assColor(hex string) (string, error) {
+	hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	var r, g, b uint8
+	if len(hex) != 6 {
+		return "", fmt.Errorf("invalid colour %q, want #RRGGBB", hex)
+	}
+	if _, err := fmt.Sscanf(hex, "%02x%02x%02x", &r, &g, &b); err != nil {
+		return "", fmt.Errorf("invalid colour %q, want #RRGGBB", hex)
+	}
+	return fmt.Sprintf("&H00%02X%02X%02X", b, g, r), nil
+}
+
+// escapeASS keeps script text from being read as override tags
+func escapeASS(text string) string {
+	return strings.NewReplacer(`\`, `/`, "{", "(", "}", ")").Replace(text)
+}
+
+func centiseconds(d time.Duration) int {
+	if d < 0 {
+		return 0
+	}
+	return int((d + 5*time.Millisecond) / (10 * time.Millisecond))
+}
+
+func formatASSTime(d time.Duration) string {
+	cs := centiseconds(d)
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
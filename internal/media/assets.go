@@ -0,0 +1,429 @@
+package media
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+)
+
+// imageExtensions are the still images the background renderer can animate
+var imageExtensions = map[string]bool{".jpg": true, ".jpeg": true, ".png": true}
+
+// videoExtensions are the B-roll clips the background renderer can use
+var videoExtensions = map[string]bool{".mp4": true, ".mov": true, ".m4v": true, ".webm": true, ".mkv": true}
+
+// Asset kinds
+const (
+	AssetImage = "image"
+	AssetVideo = "video"
+)
+
+// videoBonus gives clips the edge over equally good stills, so B-roll is
+// mixed in rather than only filling gaps
+const videoBonus = 0.5
+
+// sidecarExtensions are checked in order next to each asset, e.g. city.yaml
+// describes city.jpg
+var sidecarExtensions = []string{".yaml", ".yml", ".json"}
+
+// Asset is one background image or clip with the metadata used to match it
+// to a segment. Tags, orientation, colour and licence come from the asset's
+// sidecar; uses and last run are kept in the persisted index.
+type Asset struct {
+	Path        string   `json:"path"`
+	Kind        string   `json:"kind"`
+	Duration    float64  `json:"duration,omitempty"` // clip length in seconds
+	Tags        []string `json:"tags"`
+	Orientation string   `json:"orientation,omitempty"` // portrait, landscape or square
+	Color       string   `json:"color,omitempty"`       // dominant colour, e.g. #1E90FF
+	License     string   `json:"license,omitempty"`
+	Uses        int      `json:"uses"`
+	LastRun     int      `json:"last_run,omitempty"` // run that last used the asset, 0 if never
+}
+
+// AssetIndex is the tagged library of background assets
+type AssetIndex struct {
+	Run    int      `json:"run"` // number of the current run
+	Assets []*Asset `json:"assets"`
+
+	path       string
+	recentRuns int
+	used       map[string]bool // assets picked during this run, counted on Save
+}
+
+// assetSidecar is the metadata a sidecar file may set
+type assetSidecar struct {
+	Tags        []string `json:"tags"`
+	Orientation string   `json:"orientation"`
+	Color       string   `json:"color"`
+	License     string   `json:"license"`
+}
+
+// LoadAssetIndex scans ASSET_DIR and reads each asset's sidecar, carrying
+// usage over from the index persisted at ASSET_INDEX
+func LoadAssetIndex(cfg *config.Config) (*AssetIndex, error) {
+	index := &AssetIndex{path: cfg.AssetIndexPath, recentRuns: cfg.AssetRecentRuns, used: map[string]bool{}}
+
+	previous := map[string]*Asset{}
+	if cfg.AssetIndexPath != "" {
+		if data, err := os.ReadFile(cfg.AssetIndexPath); err == nil {
+			var saved AssetIndex
+			if err := json.Unmarshal(data, &saved); err != nil {
+				return nil, fmt.Errorf("failed to parse asset index %s: %w", cfg.AssetIndexPath, err)
+			}
+			index.Run = saved.Run
+			for _, asset := range saved.Assets {
+				previous[asset.Path] = asset
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read asset index: %w", err)
+		}
+	}
+	index.Run++
+
+	err := filepath.WalkDir(cfg.AssetDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		var kind string
+		switch {
+		case entry.IsDir():
+			return nil
+		case imageExtensions[ext]:
+			kind = AssetImage
+		case videoExtensions[ext] && cfg.AssetVideos:
+			kind = AssetVideo
+		default:
+			return nil
+		}
+
+		asset, err := scanAsset(cfg.AssetDir, path, kind)
+		if err != nil {
+			return err
+		}
+		saved, ok := previous[asset.Path]
+		if ok {
+			asset.Uses = saved.Uses
+			asset.LastRun = saved.LastRun
+		}
+		if kind == AssetVideo {
+			// Probing clips is slow, so their size and length are kept in the index
+			if ok && saved.Duration > 0 {
+				asset.Duration = saved.Duration
+				if asset.Orientation == "" {
+					asset.Orientation = saved.Orientation
+				}
+			} else {
+				probeClip(asset)
+			}
+		}
+		index.Assets = append(index.Assets, asset)
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to scan assets: %w", err)
+	}
+	return index, nil
+}
+
+// scanAsset reads an asset's sidecar, filling in what it leaves out: tags
+// from the folder and file names and the orientation from the image size
+func scanAsset(root, path, kind string) (*Asset, error) {
+	asset := &Asset{Path: filepath.ToSlash(path), Kind: kind}
+
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, ext := range sidecarExtensions {
+		data, err := os.ReadFile(base + ext)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sidecar, err := parseSidecar(data, ext)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sidecar %s: %w", base+ext, err)
+		}
+		asset.Tags = sidecar.Tags
+		asset.Orientation = strings.ToLower(sidecar.Orientation)
+		asset.Color = sidecar.Color
+		asset.License = sidecar.License
+		break
+	}
+
+	if len(asset.Tags) == 0 {
+		asset.Tags = pathTags(root, path)
+	}
+	for i, tag := range asset.Tags {
+		asset.Tags[i] = strings.ToLower(strings.TrimSpace(tag))
+	}
+	if asset.Orientation == "" && kind == AssetImage {
+		asset.Orientation = imageOrientation(path)
+	}
+	return asset, nil
+}
+
+// pathTags derives tags from the folders below root and the file name,
+// so assets/images/ai/robot2.jpg is tagged ai and robot
+func pathTags(root, path string) []string {
+	rel, err := filepath.Rel(root, strings.TrimSuffix(path, filepath.Ext(path)))
+	if err != nil {
+		return nil
+	}
+	var tags []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		words := strings.FieldsFunc(strings.ToLower(part), func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})
+		for _, word := range words {
+			if !seen[word] {
+				seen[word] = true
+				tags = append(tags, word)
+			}
+		}
+	}
+	return tags
+}
+
+// imageOrientation reads the image header, leaving unreadable images unset
+func imageOrientation(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	size, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return ""
+	}
+	return orientation(size.Width, size.Height)
+}
+
+// probeClip reads a clip's length and, unless its sidecar set one, its
+// orientation; clips ffprobe can't read keep them unset
+func probeClip(asset *Asset) {
+	out, err := exec.Command("ffprobe", "-v", "error", "-select_streams", "v:0",
+		"-show_entries", "stream=width,height:format=duration", "-of", "json", asset.Path).Output()
+	if err != nil {
+		return
+	}
+
+	var probe struct {
+		Streams []struct {
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if json.Unmarshal(out, &probe) != nil {
+		return
+	}
+	asset.Duration, _ = strconv.ParseFloat(probe.Format.Duration, 64)
+	if len(probe.Streams) > 0 && asset.Orientation == "" {
+		asset.Orientation = orientation(probe.Streams[0].Width, probe.Streams[0].Height)
+	}
+}
+
+func orientation(width, height int) string {
+	switch {
+	case height > width:
+		return "portrait"
+	case width > height:
+		return "landscape"
+	default:
+		return "square"
+	}
+}
+
+// parseSidecar reads JSON sidecars, and YAML sidecars written as flat
+// "key: value" lines with tags either inline ([a, b]) or as "- item" lines
+func parseSidecar(data []byte, ext string) (assetSidecar, error) {
+	var sidecar assetSidecar
+	if ext == ".json" {
+		err := json.Unmarshal(data, &sidecar)
+		return sidecar, err
+	}
+
+	var listKey string
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if item, ok := strings.CutPrefix(text, "- "); ok && listKey == "tags" {
+			sidecar.Tags = append(sidecar.Tags, yamlScalar(item))
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, ":")
+		if !ok {
+			return sidecar, fmt.Errorf("line %d: expected key: value", line)
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		listKey = key
+		switch key {
+		case "tags":
+			if list, ok := strings.CutPrefix(value, "["); ok {
+				for _, item := range strings.Split(strings.TrimSuffix(list, "]"), ",") {
+					if item = yamlScalar(item); item != "" {
+						sidecar.Tags = append(sidecar.Tags, item)
+					}
+				}
+			} else if value != "" {
+				sidecar.Tags = append(sidecar.Tags, yamlScalar(value))
+			}
+		case "orientation":
+			sidecar.Orientation = yamlScalar(value)
+		case "color", "colour":
+			sidecar.Color = yamlScalar(value)
+		case "license", "licence":
+			sidecar.License = yamlScalar(value)
+		}
+	}
+	return sidecar, scanner.Err()
+}
+
+// yamlScalar strips surrounding whitespace, quotes and trailing comments
+func yamlScalar(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+// Match scores every asset against the segment's tags and returns the
+// best one, or nil when the library is empty. Excluded assets, e.g. those
+// already in the video, assets used by the last few runs and clips too short
+// to cover the segment without looping are passed over while others remain.
+// Usage only changes on Save, so every output profile of a run gets the same
+// pictures.
+func (x *AssetIndex) Match(keywords []string, frameOrientation string, length time.Duration, exclude map[string]bool) *Asset {
+	if x == nil {
+		return nil
+	}
+
+	wanted := map[string]bool{}
+	for _, keyword := range keywords {
+		wanted[strings.ToLower(keyword)] = true
+	}
+
+	var best *Asset
+	bestScore := 0.0
+	for _, asset := range x.Assets {
+		score := x.score(asset, wanted, frameOrientation, length, exclude)
+		if best == nil || score > bestScore || (score == bestScore && asset.Uses < best.Uses) {
+			best, bestScore = asset, score
+		}
+	}
+	return best
+}
+
+func (x *AssetIndex) score(asset *Asset, wanted map[string]bool, frameOrientation string, length time.Duration, exclude map[string]bool) float64 {
+	score := 0.0
+	for _, tag := range asset.Tags {
+		if wanted[tag] {
+			score += 2
+		}
+	}
+
+	switch asset.Orientation {
+	case frameOrientation:
+		score++
+	case "", "square":
+	default:
+		score--
+	}
+
+	if asset.Kind == AssetVideo {
+		score += videoBonus
+		if asset.Duration > 0 && asset.Duration < length.Seconds() {
+			score--
+		}
+	}
+
+	if exclude[asset.Path] {
+		score -= 10
+	}
+	if asset.LastRun > 0 && x.recentRuns > 0 && x.Run-asset.LastRun <= x.recentRuns {
+		// The more recent the run, the stronger the penalty
+		score -= 3 * float64(x.recentRuns-(x.Run-asset.LastRun)+1) / float64(x.recentRuns)
+	}
+	return score
+}
+
+// MarkUsed records that the run picked the asset
+func (x *AssetIndex) MarkUsed(asset *Asset) {
+	if x != nil && asset != nil {
+		x.used[asset.Path] = true
+	}
+}
+
+// Save persists the index with this run's usage
+func (x *AssetIndex) Save() error {
+	if x == nil || x.path == "" {
+		return nil
+	}
+	for _, asset := range x.Assets {
+		if x.used[asset.Path] {
+			asset.Uses++
+			asset.LastRun = x.Run
+		}
+	}
+	x.used = map[string]bool{}
+	sort.Slice(x.Assets, func(i, j int) bool { return x.Assets[i].Path < x.Assets[j].Path })
+
+	data, err := json.MarshalIndent(x, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(x.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(x.path, data, 0644)
+}
+
+// UseAssets shares one asset index between services, so every output of a
+// run records its picks in the same index
+func (s *Service) UseAssets(index *AssetIndex) {
+	s.assets = index
+}
+
+// assetIndex returns the service's asset index, scanning the library on
+// first use when none was shared
+func (s *Service) assetIndex() *AssetIndex {
+	if s.assets == nil {
+		index, err := LoadAssetIndex(s.config)
+		if err != nil {
+			s.logger.Warning("Asset index unavailable: %v", err)
+			index = &AssetIndex{used: map[string]bool{}}
+		}
+		s.assets = index
+	}
+	return s.assets
+}
@@ -0,0 +1,236 @@
+package media
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+)
+
+func writePNG(t *testing.T, path string, width, height int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assetLibrary(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	root := filepath.Join(dir, "images")
+
+	writePNG(t, filepath.Join(root, "ai", "robot2.png"), 20, 40)
+	writePNG(t, filepath.Join(root, "city.png"), 40, 20)
+	writeFile(t, filepath.Join(root, "city.yaml"), `# skyline at night
+tags:
+  - city
+  - "night"
+orientation: Portrait
+color: '#1E90FF'  # blue
+license: CC0
+`)
+	writePNG(t, filepath.Join(root, "chip.png"), 30, 30)
+	writeFile(t, filepath.Join(root, "chip.json"), `{"tags": ["Hardware", "chip"], "license": "CC-BY 4.0"}`)
+
+	return &config.Config{
+		AssetDir:        root,
+		AssetIndexPath:  filepath.Join(dir, "index.json"),
+		AssetRecentRuns: 3,
+	}
+}
+
+func TestLoadAssetIndex(t *testing.T) {
+	cfg := assetLibrary(t)
+	index, err := LoadAssetIndex(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assets := map[string]*Asset{}
+	for _, asset := range index.Assets {
+		assets[filepath.Base(asset.Path)] = asset
+	}
+	if len(assets) != 3 {
+		t.Fatalf("indexed %d assets, want 3", len(index.Assets))
+	}
+
+	robot := assets["robot2.png"]
+	if !reflect.DeepEqual(robot.Tags, []string{"ai", "robot"}) || robot.Orientation != "portrait" {
+		t.Errorf("robot2.png without a sidecar = %+v", robot)
+	}
+	city := assets["city.png"]
+	want := Asset{Path: city.Path, Kind: AssetImage, Tags: []string{"city", "night"}, Orientation: "portrait", Color: "#1E90FF", License: "CC0"}
+	if !reflect.DeepEqual(*city, want) {
+		t.Errorf("city.png from YAML sidecar = %+v, want %+v", *city, want)
+	}
+	chip := assets["chip.png"]
+	if !reflect.DeepEqual(chip.Tags, []string{"hardware", "chip"}) || chip.Orientation != "square" || chip.License != "CC-BY 4.0" {
+		t.Errorf("chip.png from JSON sidecar = %+v", chip)
+	}
+	if index.Run != 1 {
+		t.Errorf("first run numbered %d", index.Run)
+	}
+}
+
+func TestParseSidecar_InlineTags(t *testing.T) {
+	sidecar, err := parseSidecar([]byte("tags: [ocean, 'waves', sunset]\ncolour: orange\n"), ".yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sidecar.Tags, []string{"ocean", "waves", "sunset"}) || sidecar.Color != "orange" {
+		t.Errorf("sidecar = %+v", sidecar)
+	}
+
+	if _, err := parseSidecar([]byte("tags\n"), ".yaml"); err == nil {
+		t.Error("malformed sidecar accepted")
+	}
+}
+
+func TestAssetIndex_Match(t *testing.T) {
+	index, err := LoadAssetIndex(assetLibrary(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var city string
+	for _, asset := range index.Assets {
+		if filepath.Base(asset.Path) == "city.png" {
+			city = asset.Path
+		}
+	}
+
+	tests := []struct {
+		keywords []string
+		exclude  map[string]bool
+		want     string
+	}{
+		{[]string{"the", "city", "never", "sleeps"}, nil, "city.png"},
+		{[]string{"ai", "networks"}, nil, "robot2.png"}, // tagged by its folder
+		{[]string{"a", "new", "chip"}, nil, "chip.png"},
+		{[]string{"city", "lights"}, map[string]bool{city: true}, ""}, // already in the video
+	}
+	for _, tt := range tests {
+		asset := index.Match(tt.keywords, "portrait", 0, tt.exclude)
+		if asset == nil {
+			t.Fatalf("no match for %v", tt.keywords)
+		}
+		if tt.want != "" && filepath.Base(asset.Path) != tt.want {
+			t.Errorf("Match(%v) = %s, want %s", tt.keywords, asset.Path, tt.want)
+		}
+		if tt.exclude[asset.Path] {
+			t.Errorf("Match(%v) picked excluded %s", tt.keywords, asset.Path)
+		}
+	}
+
+	if asset := (&AssetIndex{}).Match([]string{"city"}, "portrait", 0, nil); asset != nil {
+		t.Errorf("empty library matched %+v", asset)
+	}
+}
+
+func TestAssetIndex_AvoidsRecentRuns(t *testing.T) {
+	cfg := assetLibrary(t)
+	keywords := []string{"city", "chip"} // city.png leads only on orientation
+
+	index, err := LoadAssetIndex(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := index.Match(keywords, "portrait", 0, nil)
+	index.MarkUsed(first)
+	if again := index.Match(keywords, "portrait", 0, nil); again != first {
+		t.Errorf("pick changed within the run: %s then %s", first.Path, again.Path)
+	}
+	if err := index.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	next, err := LoadAssetIndex(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Run != 2 {
+		t.Errorf("second run numbered %d", next.Run)
+	}
+	second := next.Match(keywords, "portrait", 0, nil)
+	if second.Path == first.Path {
+		t.Errorf("run 2 reused %s from run 1", first.Path)
+	}
+	for _, asset := range next.Assets {
+		if asset.Path == first.Path && (asset.Uses != 1 || asset.LastRun != 1) {
+			t.Errorf("persisted usage = %+v", asset)
+		}
+	}
+}
+
+func TestLoadAssetIndex_Videos(t *testing.T) {
+	cfg := assetLibrary(t)
+	cfg.AssetVideos = true
+	clip := filepath.Join(cfg.AssetDir, "broll", "traffic.mp4")
+	if err := os.MkdirAll(filepath.Dir(clip), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, clip, "not really a video")
+	writeFile(t, filepath.Join(cfg.AssetDir, "broll", "traffic.json"), `{"tags": ["city", "cars"], "orientation": "landscape"}`)
+	writeFile(t, cfg.AssetIndexPath, `{"run": 4, "assets": [{"path": "`+filepath.ToSlash(clip)+`", "kind": "video", "duration": 12.5, "uses": 2}]}`)
+
+	index, err := LoadAssetIndex(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var video *Asset
+	for _, asset := range index.Assets {
+		if asset.Kind == AssetVideo {
+			video = asset
+		}
+	}
+	if video == nil {
+		t.Fatalf("clip not indexed: %+v", index.Assets)
+	}
+	want := Asset{Path: filepath.ToSlash(clip), Kind: AssetVideo, Duration: 12.5, Tags: []string{"city", "cars"}, Orientation: "landscape", Uses: 2}
+	if !reflect.DeepEqual(*video, want) {
+		t.Errorf("clip = %+v, want %+v", *video, want)
+	}
+
+	cfg.AssetVideos = false
+	if index, err = LoadAssetIndex(cfg); err != nil {
+		t.Fatal(err)
+	}
+	for _, asset := range index.Assets {
+		if asset.Kind != AssetImage {
+			t.Errorf("ASSET_VIDEOS=false indexed %s", asset.Path)
+		}
+	}
+}
+
+func TestAssetIndex_MatchMixesClips(t *testing.T) {
+	still := &Asset{Path: "city.jpg", Kind: AssetImage, Tags: []string{"city"}}
+	clip := &Asset{Path: "city.mp4", Kind: AssetVideo, Duration: 4, Tags: []string{"city"}}
+	index := &AssetIndex{Assets: []*Asset{still, clip}}
+
+	if got := index.Match([]string{"city"}, "portrait", 3*time.Second, nil); got != clip {
+		t.Errorf("equally tagged clip should win, got %s", got.Path)
+	}
+	if got := index.Match([]string{"city"}, "portrait", 6*time.Second, nil); got != still {
+		t.Errorf("clip shorter than the segment should lose, got %s", got.Path)
+	}
+}
@@ -0,0 +1,80 @@
+package media
+
+import (
+	"fmt"
+	"time"
+)
+
+// mixAudio adds the narration and optional music to the graph, ending the
+// mix with the given filter (if any), and returns the label of the final
+// mix. The music is looped to cover the narration, faded in and out, and
+// ducked under the voice by a sidechain compressor keyed on the narration.
+func (s *Service) mixAudio(g *filterGraph, cfg RenderConfig, final string) string {
+	narration := g.input("-i", cfg.Narration)
+	narrationIn := []string{stream(narration, "a")}
+
+	if cfg.Music == "" {
+		filters := "anull"
+		if final != "" {
+			filters = final
+		}
+		g.chain(narrationIn, filters, "mixed")
+		return "mixed"
+	}
+
+	music := g.input("-stream_loop", "-1", "-i", cfg.Music)
+	g.chain(narrationIn, "asplit=2", "narr", "voice")
+	// The compressor stops with its shortest input, so the key is padded
+	// to let the music play on through the outro
+	g.chain([]string{"voice"}, "apad", "key")
+	g.chain([]string{stream(music, "a")}, s.musicBedFilter(cfg.Duration), "bed")
+	g.chain([]string{"bed", "key"}, s.duckFilter(), "ducked")
+
+	// A trimmed music bed sets the length; otherwise the narration does
+	mixLength := "first"
+	if cfg.Duration > 0 {
+		mixLength = "longest"
+	}
+	filters := "amix=inputs=2:duration=" + mixLength
+	if final != "" {
+		filters += "," + final
+	}
+	g.chain([]string{"narr", "ducked"}, filters, "mixed")
+	return "mixed"
+}
+
+// musicBedFilter sets the music level, fades it at the edges and trims it to
+// the video length; the fade out and trim are skipped when the length is unknown
+func (s *Service) musicBedFilter(duration time.Duration) string {
+	filters := fmt.Sprintf("volume=%gdB", s.config.MusicLevelDB)
+	if fadeIn := s.config.MusicFadeIn; fadeIn > 0 {
+		filters += fmt.Sprintf(",afade=t=in:st=0:d=%g", fadeIn)
+	}
+	if fadeOut := s.config.MusicFadeOut; fadeOut > 0 && duration > 0 {
+		start := duration.Seconds() - fadeOut
+		if start < 0 {
+			start, fadeOut = 0, duration.Seconds()
+		}
+		filters += fmt.Sprintf(",afade=t=out:st=%.3f:d=%g", start, fadeOut)
+	}
+	if duration > 0 {
+		filters += fmt.Sprintf(",atrim=end=%.3f", duration.Seconds())
+	}
+	return filters
+}
+
+// duckFilter compresses the music while the narration is above the threshold
+func (s *Service) duckFilter() string {
+	return fmt.Sprintf("sidechaincompress=threshold=%g:ratio=%g:attack=%g:release=%g",
+		s.config.DuckThreshold, s.config.DuckRatio, s.config.DuckAttackMs, s.config.DuckReleaseMs)
+}
+
+// renderAudioFilter ends the mix for the render: normalized, then padded
+// with silence so -shortest stops at the end of the video rather than the
+// end of the narration
+func (s *Service) renderAudioFilter(cfg RenderConfig) string {
+	if loudness := s.loudnessFilter(cfg.loudness); loudness != "" {
+		return loudness + ",apad"
+	}
+	return "apad"
+}
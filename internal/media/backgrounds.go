@@ -8,33 +8,70 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+	"unicode"
+
+	"github.com/g-laliotis/convertbox/internal/textnorm"
+	"github.com/g-laliotis/convertbox/internal/visuals"
 )
 
+// visualKeywordsPerSentence caps the keywords TF-IDF picks for a sentence
+const visualKeywordsPerSentence = 3
+
 // BackgroundSegment represents a timed background change
 type BackgroundSegment struct {
-	StartTime time.Duration
-	EndTime   time.Duration
-	ImagePath string
-	Keywords  []string
+	StartTime  time.Duration
+	EndTime    time.Duration
+	ImagePath  string
+	VideoPath  string        // B-roll clip shown instead of an image
+	ClipLength time.Duration // length of VideoPath, zero when unknown
+	Keywords   []string
+	Transition string // xfade transition into this segment
+	Motion     Motion // Ken Burns move over the image
 }
 
-// CreateDynamicBackground creates a video with changing backgrounds based on script content
-func (s *Service) CreateDynamicBackground(ctx context.Context, script, outPath string, duration time.Duration) error {
+// CreateDynamicBackground creates a video with changing backgrounds based on
+// script content, keeping the per-segment clips in workDir
+func (s *Service) CreateDynamicBackground(ctx context.Context, script, workDir, outPath string, duration time.Duration) error {
 	s.logger.Info("Creating dynamic background based on script content")
 
 	// Analyze script and create segments
 	segments := s.analyzeScriptForBackgrounds(script, duration)
-	
+	return s.renderBackgroundSegments(ctx, segments, workDir, outPath, duration)
+}
+
+// CreateSegmentedBackground creates one background per script segment, timed
+// by each segment's share of the spoken words, keeping the clips in workDir
+func (s *Service) CreateSegmentedBackground(ctx context.Context, scriptSegments []ScriptSegment, workDir, outPath string, duration time.Duration) error {
+	s.logger.Info("Creating dynamic background for %d script segments", len(scriptSegments))
+
+	segments := s.analyzeSegmentsForBackgrounds(scriptSegments, duration)
+	return s.renderBackgroundSegments(ctx, segments, workDir, outPath, duration)
+}
+
+func (s *Service) renderBackgroundSegments(ctx context.Context, segments []BackgroundSegment, workDir, outPath string, duration time.Duration) error {
 	if len(segments) == 0 {
 		// Fallback to static background
 		return s.CreateBackground(ctx, outPath, duration)
 	}
 
-	// Create individual background videos for each segment
+	random := s.motionRandom()
+	overlap := s.transitionDuration(segments)
+	if overlap > 0 {
+		s.assignTransitions(segments, random)
+	}
+	for i := range segments {
+		segments[i].Motion = newMotion(random)
+	}
+
+	// Create individual background videos for each segment; all but the
+	// last run on into the transition that follows them
 	var segmentPaths []string
 	for i, segment := range segments {
-		segmentPath := fmt.Sprintf("build/segment_%d.mp4", i)
+		segmentPath := filepath.Join(workDir, fmt.Sprintf("segment_%d.mp4", i))
 		segmentDuration := segment.EndTime - segment.StartTime
+		if i < len(segments)-1 {
+			segmentDuration += overlap
+		}
 		
 		if err := s.createSegmentBackground(ctx, segment, segmentPath, segmentDuration); err != nil {
 			s.logger.Warning("Failed to create segment %d, using fallback", i)
@@ -46,8 +83,19 @@ func (s *Service) CreateDynamicBackground(ctx context.Context, script, outPath s
 		segmentPaths = append(segmentPaths, segmentPath)
 	}
 
+	if overlap > 0 {
+		err := s.joinWithTransitions(ctx, segmentPaths, segments, overlap, outPath)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.logger.Warning("Transitions failed, joining segments with hard cuts: %v", err)
+	}
+
 	// Concatenate all segments
-	if err := s.concatenateSegments(ctx, segmentPaths, outPath); err != nil {
+	if err := s.concatenateSegments(ctx, segmentPaths, workDir, outPath); err != nil {
 		// If concatenation fails, use first segment as fallback
 		if len(segmentPaths) > 0 {
 			return exec.CommandContext(ctx, "cp", segmentPaths[0], outPath).Run()
@@ -57,152 +105,166 @@ func (s *Service) CreateDynamicBackground(ctx context.Context, script, outPath s
 	return nil
 }
 
+// analyzeScriptForBackgrounds gives every sentence of a plain script its
+// own background, keyed by the sentence's TF-IDF keywords
 func (s *Service) analyzeScriptForBackgrounds(script string, totalDuration time.Duration) []BackgroundSegment {
-	// Clean script
-	cleanScript := strings.ReplaceAll(script, `"`, "")
-	words := strings.Fields(strings.ToLower(cleanScript))
-	
-	// Define keyword mappings to image categories
-	keywordMap := map[string]string{
-		"artificial":   "ai",
-		"intelligence": "ai", 
-		"ai":          "ai",
-		"robot":       "ai",
-		"machine":     "ai",
-		"neural":      "ai",
-		"deep":        "ai",
-		"learning":    "ai",
-		"algorithm":   "tech",
-		"data":        "tech",
-		"computer":    "tech",
-		"digital":     "tech",
-		"technology":  "tech",
-		"software":    "tech",
-		"code":        "tech",
-		"programming": "tech",
-		"tool":        "tools",
-		"tools":       "tools",
-		"app":         "tools",
-		"application": "tools",
-		"platform":    "tools",
-		"service":     "tools",
-	}
-
-	// Split script into roughly equal time segments
-	segmentCount := 3 // 3 background changes per video
-	segmentDuration := totalDuration / time.Duration(segmentCount)
-	wordsPerSegment := len(words) / segmentCount
-	
-	var segments []BackgroundSegment
-	
-	for i := 0; i < segmentCount; i++ {
-		startTime := time.Duration(i) * segmentDuration
-		endTime := startTime + segmentDuration
-		if i == segmentCount-1 {
-			endTime = totalDuration
-		}
+	sentences := textnorm.SplitSentences(strings.ReplaceAll(script, `"`, ""))
+	keywords := visuals.Extract(sentences, visualKeywordsPerSentence)
+
+	var scriptSegments []ScriptSegment
+	for i, sentence := range sentences {
+		scriptSegments = append(scriptSegments, ScriptSegment{Text: sentence, Keywords: keywords[i]})
+	}
+	return s.analyzeSegmentsForBackgrounds(scriptSegments, totalDuration)
+}
 
-		// Analyze words in this segment
-		startWord := i * wordsPerSegment
-		endWord := startWord + wordsPerSegment
-		if endWord > len(words) {
-			endWord = len(words)
+// analyzeSegmentsForBackgrounds times one background per script segment,
+// merging segments too short to hold a picture into the one before. Segments
+// use their narration timings when they have them and their share of the
+// spoken words otherwise; the last runs on to the end of the video.
+func (s *Service) analyzeSegmentsForBackgrounds(scriptSegments []ScriptSegment, totalDuration time.Duration) []BackgroundSegment {
+	starts := segmentStarts(scriptSegments, totalDuration)
+	if starts == nil {
+		return nil
+	}
+	minDuration := time.Duration(s.config.BackgroundMinSeconds * float64(time.Second))
+	if max := totalDuration / 2; minDuration > max {
+		minDuration = max
+	}
+
+	var segments []BackgroundSegment
+	for i, segment := range scriptSegments {
+		endTime := totalDuration
+		if i+1 < len(scriptSegments) {
+			endTime = starts[i+1]
 		}
 
-		category := s.detectCategory(words[startWord:endWord], keywordMap)
-		imagePath := s.findBestImage(category)
+		// Visual keywords are matched alongside the spoken words
+		words := strings.FieldsFunc(strings.ToLower(segment.Text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		keywords := append(append([]string{}, segment.Keywords...), words...)
 
+		last := len(segments) - 1
+		if last >= 0 && segments[last].EndTime-segments[last].StartTime < minDuration {
+			segments[last].EndTime = endTime
+			segments[last].Keywords = append(segments[last].Keywords, keywords...)
+			continue
+		}
 		segments = append(segments, BackgroundSegment{
-			StartTime: startTime,
-			EndTime:   endTime,
-			ImagePath: imagePath,
-			Keywords:  words[startWord:endWord],
+			StartTime:  starts[i],
+			EndTime:    endTime,
+			Keywords:   keywords,
+			Transition: segment.Transition,
 		})
 	}
 
+	// A short final segment joins the one before it
+	if n := len(segments); n > 1 && segments[n-1].EndTime-segments[n-1].StartTime < minDuration {
+		segments[n-2].EndTime = segments[n-1].EndTime
+		segments[n-2].Keywords = append(segments[n-2].Keywords, segments[n-1].Keywords...)
+		segments = segments[:n-1]
+	}
+
+	picked := map[string]bool{}
+	for i := range segments {
+		s.pickAsset(&segments[i], picked)
+	}
 	return segments
 }
 
-func (s *Service) detectCategory(words []string, keywordMap map[string]string) string {
-	categoryCount := make(map[string]int)
-	
-	for _, word := range words {
-		if category, exists := keywordMap[word]; exists {
-			categoryCount[category]++
-		}
+// segmentStarts returns when each segment starts: its narration timing when
+// every segment has one, otherwise its share of the spoken words
+func segmentStarts(segments []ScriptSegment, totalDuration time.Duration) []time.Duration {
+	if len(segments) == 0 {
+		return nil
 	}
 
-	// Find most frequent category
-	maxCount := 0
-	bestCategory := "tech" // default
-	for category, count := range categoryCount {
-		if count > maxCount {
-			maxCount = count
-			bestCategory = category
+	timed := true
+	for _, segment := range segments {
+		timed = timed && segment.End > 0
+	}
+	starts := make([]time.Duration, len(segments))
+	if timed {
+		// The first background covers any silence before the narration
+		for i := 1; i < len(segments); i++ {
+			starts[i] = segments[i].Start
+			if starts[i] > totalDuration {
+				starts[i] = totalDuration // cut short, e.g. by --test
+			}
 		}
+		return starts
 	}
 
-	return bestCategory
+	weights := make([]int, len(segments))
+	totalWords := 0
+	for i, segment := range segments {
+		weights[i] = len(strings.Fields(segment.Text))
+		totalWords += weights[i]
+	}
+	if totalWords == 0 {
+		return nil
+	}
+	spoken := 0
+	for i := range segments {
+		starts[i] = totalDuration * time.Duration(spoken) / time.Duration(totalWords)
+		spoken += weights[i]
+	}
+	return starts
 }
 
-func (s *Service) findBestImage(category string) string {
-	// Look for images in category-specific folders
-	categoryPaths := []string{
-		fmt.Sprintf("assets/images/%s", category),
-		"assets/images/tech", // fallback
-		"assets/images",      // general fallback
+// keywordMap loads KEYWORD_MAP on first use; without it keywords only
+// match asset tags directly
+func (s *Service) keywordMap() visuals.KeywordMap {
+	if s.keywords == nil {
+		keywordMap, err := visuals.LoadKeywordMap(s.config.KeywordMapPath)
+		if err != nil {
+			s.logger.Warning("Keyword map unavailable: %v", err)
+			keywordMap = visuals.KeywordMap{}
+		}
+		s.keywords = keywordMap
 	}
+	return s.keywords
+}
 
-	for _, dir := range categoryPaths {
-		// Check each extension separately
-		extensions := []string{"*.jpg", "*.jpeg", "*.png"}
-		for _, ext := range extensions {
-			if files, err := filepath.Glob(filepath.Join(dir, ext)); err == nil && len(files) > 0 {
-				return files[0] // Return first available image
-			}
-		}
+// pickAsset matches the segment's keywords against the asset library,
+// avoiding assets already picked for this video
+func (s *Service) pickAsset(segment *BackgroundSegment, picked map[string]bool) {
+	w, h := s.frameSize()
+	tags := s.keywordMap().Tags(segment.Keywords)
+	asset := s.assetIndex().Match(tags, orientation(w, h), segment.EndTime-segment.StartTime, picked)
+	if asset == nil {
+		return // No asset found
 	}
+	picked[asset.Path] = true
+	s.assets.MarkUsed(asset)
 
-	return "" // No image found
+	if asset.Kind == AssetVideo {
+		segment.VideoPath = asset.Path
+		segment.ClipLength = time.Duration(asset.Duration * float64(time.Second))
+	} else {
+		segment.ImagePath = asset.Path
+	}
 }
 
 func (s *Service) createSegmentBackground(ctx context.Context, segment BackgroundSegment, outPath string, duration time.Duration) error {
+	if segment.VideoPath != "" {
+		return s.runFFmpeg(ctx, s.videoClipArgs(segment.VideoPath, outPath, duration, segment.ClipLength))
+	}
 	if segment.ImagePath == "" {
 		return fmt.Errorf("no image path provided")
 	}
 
-	sec := int(duration.Seconds())
-	
-	// Create zooming/panning effect based on segment position
-	zoomEffect := "zoompan=z='min(zoom+0.002,1.8)':d=125:x='iw/2-(iw/zoom/2)':y='ih/2-(ih/zoom/2)'"
-	
-	cmd := exec.CommandContext(ctx, "ffmpeg", "-y",
-		"-loop", "1", "-i", segment.ImagePath,
-		"-t", fmt.Sprintf("%d", sec),
-		"-vf", fmt.Sprintf("scale=1080:1920:force_original_aspect_ratio=increase,crop=1080:1920,%s", zoomEffect),
-		"-c:v", "libx264", "-preset", "ultrafast", "-pix_fmt", "yuv420p",
-		outPath,
-	)
-	
-	return cmd.Run()
+	return s.runFFmpeg(ctx, s.imageClipArgs(segment.ImagePath, outPath, duration, segment.Motion))
 }
 
 func (s *Service) createFallbackSegment(ctx context.Context, outPath string, duration time.Duration) error {
-	sec := int(duration.Seconds())
-	
-	cmd := exec.CommandContext(ctx, "ffmpeg", "-y",
-		"-f", "lavfi", "-t", fmt.Sprintf("%d", sec),
-		"-i", "color=c=#0f0f23:s=1080x1920",
-		"-c:v", "libx264", "-preset", "ultrafast", "-pix_fmt", "yuv420p",
-		outPath,
-	)
-	
-	return cmd.Run()
+	return s.runFFmpeg(ctx, s.colorClipArgs(outPath, duration))
 }
 
-func (s *Service) concatenateSegments(ctx context.Context, segmentPaths []string, outPath string) error {
+func (s *Service) concatenateSegments(ctx context.Context, segmentPaths []string, workDir, outPath string) error {
 	// Create concat file
-	concatFile := "build/concat.txt"
+	concatFile := filepath.Join(workDir, "concat.txt")
 	var concatContent strings.Builder
 	
 	for _, path := range segmentPaths {
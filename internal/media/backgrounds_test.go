@@ -0,0 +1,102 @@
+package media
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+	"github.com/g-laliotis/convertbox/internal/logger"
+)
+
+func TestService_AnalyzeSegmentsForBackgrounds(t *testing.T) {
+	service := NewService(&config.Config{}, logger.New())
+
+	segments := service.analyzeSegmentsForBackgrounds([]ScriptSegment{
+		{Text: "Stop scrolling right now"},
+		{Text: "This app builds websites for you in seconds", Keywords: []string{"tools"}},
+		{Text: "Subscribe for more insights"},
+	}, 16*time.Second)
+
+	if len(segments) != 3 {
+		t.Fatalf("got %d segments, want 3", len(segments))
+	}
+	if segments[0].EndTime != 4*time.Second || segments[1].EndTime != 12*time.Second {
+		t.Errorf("segments not timed by word count: %v, %v", segments[0].EndTime, segments[1].EndTime)
+	}
+	if segments[2].EndTime != 16*time.Second {
+		t.Errorf("last segment ends at %v, want 16s", segments[2].EndTime)
+	}
+}
+
+func TestService_AnalyzeSegmentsForBackgrounds_Timed(t *testing.T) {
+	cfg := assetLibrary(t)
+	cfg.VideoWidth, cfg.VideoHeight = 1080, 1920
+	cfg.BackgroundMinSeconds = 2
+	cfg.KeywordMapPath = filepath.Join(t.TempDir(), "keywords.txt")
+	writeFile(t, cfg.KeywordMapPath, "neural network = ai\nskyline = city\n")
+	service := NewService(cfg, logger.New())
+
+	sentences := []ScriptSegment{
+		{Text: "Look up.", Keywords: []string{"skyline", "night"}, Start: 300 * time.Millisecond, End: time.Second},
+		{Text: "Neural networks now design skyscrapers.", Keywords: []string{"neural network"}, Start: 1200 * time.Millisecond, End: 4 * time.Second, Transition: "fade"},
+		{Text: "Even chips.", Keywords: []string{"chip"}, Start: 4200 * time.Millisecond, End: 5 * time.Second},
+		{Text: "Wow.", Start: 5100 * time.Millisecond, End: 5500 * time.Millisecond},
+	}
+	segments := service.analyzeSegmentsForBackgrounds(sentences, 7*time.Second)
+
+	// "Look up." is too short and shares the next sentence's picture; "Wow."
+	// and the outro join the chips sentence
+	type span struct {
+		start, end time.Duration
+		image      string
+		transition string
+	}
+	var got []span
+	for _, segment := range segments {
+		got = append(got, span{segment.StartTime, segment.EndTime, filepath.Base(segment.ImagePath), segment.Transition})
+	}
+	want := []span{
+		{0, 4200 * time.Millisecond, "city.png", ""},
+		{4200 * time.Millisecond, 7 * time.Second, "chip.png", ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("segments = %+v, want %+v", got, want)
+	}
+}
+
+func TestService_AnalyzeSegmentsForBackgrounds_DistinctImages(t *testing.T) {
+	cfg := assetLibrary(t)
+	service := NewService(cfg, logger.New())
+
+	segments := service.analyzeSegmentsForBackgrounds([]ScriptSegment{
+		{Text: "one two three"},
+		{Text: "four"},
+	}, 8*time.Second)
+
+	if len(segments) != 2 || segments[1].StartTime != 6*time.Second || segments[1].EndTime != 8*time.Second {
+		t.Errorf("segments = %+v", segments)
+	}
+	if segments[0].ImagePath == segments[1].ImagePath {
+		t.Errorf("both segments show %s", segments[0].ImagePath)
+	}
+}
+
+func TestService_AnalyzeScriptForBackgrounds(t *testing.T) {
+	cfg := assetLibrary(t)
+	cfg.BackgroundMinSeconds = 1
+	service := NewService(cfg, logger.New())
+
+	segments := service.analyzeScriptForBackgrounds(`A.I. robots are everywhere. The city lights never fade. New chips arrive daily. Subscribe!`, 12*time.Second)
+	// The short call to action shares the last sentence's background
+	if len(segments) != 3 {
+		t.Fatalf("got %d segments, want one per sentence: %+v", len(segments), segments)
+	}
+	if image := filepath.Base(segments[1].ImagePath); image != "city.png" {
+		t.Errorf("city sentence shows %s", image)
+	}
+	if segments[2].EndTime != 12*time.Second {
+		t.Errorf("last segment ends at %v", segments[2].EndTime)
+	}
+}
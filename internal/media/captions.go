@@ -0,0 +1,175 @@
+package media
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/align"
+)
+
+// Cue is one caption on screen: its words with their timings, laid out into
+// lines. Cues are the caption model every subtitle format is written from.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Words []align.Word
+	Lines []string
+}
+
+// Text returns the cue's lines joined with newlines
+func (c Cue) Text() string {
+	return strings.Join(c.Lines, "\n")
+}
+
+// captionWriters write cues in each supported subtitle format, keyed by file extension
+var captionWriters = map[string]func(s *Service, w *bufio.Writer, cues []Cue) error{
+	"srt": (*Service).writeSRT,
+	"vtt": (*Service).writeVTT,
+	"ass": (*Service).writeASS,
+}
+
+// CaptionFormats lists the subtitle formats WriteCaptions supports
+func CaptionFormats() []string {
+	var formats []string
+	for format := range captionWriters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// TranscriptWords returns the caption words of all segments in order; word
+// timings from the align package are indexed against this list
+func TranscriptWords(segments []ScriptSegment) []string {
+	var words []string
+	for _, segment := range segments {
+		words = append(words, segmentWords(segment)...)
+	}
+	return words
+}
+
+// AlignedCaptions pairs the word timings with the script's words and
+// segments them into cues. Caption text comes from the script so
+// recognition quirks never reach the screen.
+func (s *Service) AlignedCaptions(segments []ScriptSegment, words []align.Word) ([]Cue, error) {
+	transcript := TranscriptWords(segments)
+	if len(transcript) == 0 {
+		return nil, fmt.Errorf("no words found in script")
+	}
+	if len(transcript) != len(words) {
+		return nil, fmt.Errorf("have %d word timings for %d caption words", len(words), len(transcript))
+	}
+
+	var grouped [][]align.Word
+	index := 0
+	for _, segment := range segments {
+		texts := segmentWords(segment)
+		timed := make([]align.Word, len(texts))
+		for i, text := range texts {
+			timed[i] = words[index+i]
+			timed[i].Text = text
+		}
+		index += len(texts)
+		grouped = append(grouped, timed)
+	}
+	return s.segmenter().Segment(grouped), nil
+}
+
+// EvenCaptions spreads the words evenly over the narration, for when no word
+// timings are available
+func (s *Service) EvenCaptions(audioPath string, segments []ScriptSegment) ([]Cue, error) {
+	dur, err := s.getAudioDuration(audioPath)
+	if err != nil {
+		return nil, err
+	}
+	return s.AlignedCaptions(segments, evenWords(segments, dur))
+}
+
+// WriteCaptions writes the cues to outPath in the given format (srt, vtt or ass)
+func (s *Service) WriteCaptions(cues []Cue, format, outPath string) error {
+	write, ok := captionWriters[strings.ToLower(format)]
+	if !ok {
+		return fmt.Errorf("unknown caption format %q (available: %s)", format, strings.Join(CaptionFormats(), ", "))
+	}
+
+	file, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	if err := write(s, w, cues); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func (s *Service) writeSRT(w *bufio.Writer, cues []Cue) error {
+	for i, cue := range cues {
+		fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n",
+			i+1,
+			s.formatSRTTime(cue.Start),
+			s.formatSRTTime(cue.End),
+			cue.Text(),
+		)
+	}
+	return nil
+}
+
+func (s *Service) writeVTT(w *bufio.Writer, cues []Cue) error {
+	w.WriteString("WEBVTT\n\n")
+	escape := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	for _, cue := range cues {
+		fmt.Fprintf(w, "%s --> %s\n%s\n\n",
+			formatVTTTime(cue.Start),
+			formatVTTTime(cue.End),
+			escape.Replace(cue.Text()),
+		)
+	}
+	return nil
+}
+
+func (s *Service) segmenter() Segmenter {
+	return Segmenter{
+		MaxCharsPerLine:   s.config.CaptionMaxChars,
+		MaxLines:          s.config.CaptionMaxLines,
+		MinDuration:       time.Duration(s.config.CaptionMinSeconds * float64(time.Second)),
+		MaxDuration:       time.Duration(s.config.CaptionMaxSeconds * float64(time.Second)),
+		MaxCharsPerSecond: s.config.CaptionMaxCPS,
+	}
+}
+
+// evenWords spreads the script's words evenly over a duration
+func evenWords(segments []ScriptSegment, duration time.Duration) []align.Word {
+	transcript := TranscriptWords(segments)
+	words := make([]align.Word, len(transcript))
+	for i, text := range transcript {
+		words[i] = align.Word{
+			Text:  text,
+			Start: duration * time.Duration(i) / time.Duration(len(transcript)),
+			End:   duration * time.Duration(i+1) / time.Duration(len(transcript)),
+		}
+	}
+	return words
+}
+
+// segmentWords cleans quotes from the script and splits it into words
+func segmentWords(segment ScriptSegment) []string {
+	return strings.Fields(strings.ReplaceAll(segment.Text, `"`, ""))
+}
+
+func formatVTTTime(d time.Duration) string {
+	h := int(d / time.Hour)
+	m := int(d/time.Minute) % 60
+	sec := int(d/time.Second) % 60
+	ms := int(d/time.Millisecond) % 1000
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, sec, ms)
+}
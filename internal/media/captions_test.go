@@ -0,0 +1,120 @@
+package media
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/align"
+	"github.com/g-laliotis/convertbox/internal/config"
+	"github.com/g-laliotis/convertbox/internal/logger"
+)
+
+func TestService_AlignedCaptions(t *testing.T) {
+	service := NewService(&config.Config{}, logger.New())
+	dir := t.TempDir()
+
+	segments := []ScriptSegment{{Text: "One two three <four> five."}, {Text: "Six & seven."}}
+	var words []align.Word
+	for i, text := range TranscriptWords(segments) {
+		start := time.Duration(i) * 300 * time.Millisecond
+		if i >= 5 {
+			start += time.Second // long pause before the second segment
+		}
+		words = append(words, align.Word{Text: text, Start: start, End: start + 250*time.Millisecond})
+	}
+
+	cues, err := service.AlignedCaptions(segments, words)
+	if err != nil {
+		t.Fatalf("AlignedCaptions failed: %v", err)
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"srt", "1\n00:00:00,000 --> 00:00:01,450\nOne two three <four> five.\n\n" +
+			"2\n00:00:02,500 --> 00:00:03,350\nSix & seven.\n\n"},
+		{"vtt", "WEBVTT\n\n" +
+			"00:00:00.000 --> 00:00:01.450\nOne two three &lt;four&gt; five.\n\n" +
+			"00:00:02.500 --> 00:00:03.350\nSix &amp; seven.\n\n"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, "subs."+tt.format)
+		if err := service.WriteCaptions(cues, tt.format, path); err != nil {
+			t.Fatalf("WriteCaptions(%s) failed: %v", tt.format, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("%s =\n%s\nwant\n%s", tt.format, data, tt.want)
+		}
+	}
+
+	if err := service.WriteCaptions(cues, "sbv", filepath.Join(dir, "subs.sbv")); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+	if _, err := service.AlignedCaptions(segments, words[:3]); err == nil {
+		t.Error("expected error when timings don't match the caption words")
+	}
+}
+
+func TestService_WriteCaptionsASS(t *testing.T) {
+	cfg := &config.Config{
+		CaptionFont:           "Arial Black",
+		CaptionFontSize:       80,
+		CaptionOutline:        5,
+		CaptionShadow:         2,
+		CaptionColor:          "#FFFFFF",
+		CaptionHighlightColor: "#FFD400",
+		CaptionPosition:       "bottom",
+		CaptionMargin:         480,
+		CaptionMaxChars:       12,
+		CaptionMaxLines:       2,
+		VideoWidth:            1080,
+		VideoHeight:           1920,
+	}
+	service := NewService(cfg, logger.New())
+	assFile := filepath.Join(t.TempDir(), "subs.ass")
+
+	segments := []ScriptSegment{{Text: "Meet {the} robot."}}
+	words := []align.Word{
+		{Start: 0, End: 300 * time.Millisecond},
+		{Start: 300 * time.Millisecond, End: 500 * time.Millisecond},
+		{Start: 700 * time.Millisecond, End: 1250 * time.Millisecond},
+	}
+
+	cues, err := service.AlignedCaptions(segments, words)
+	if err != nil {
+		t.Fatalf("AlignedCaptions failed: %v", err)
+	}
+	if err := service.WriteCaptions(cues, "ass", assFile); err != nil {
+		t.Fatalf("WriteCaptions failed: %v", err)
+	}
+
+	data, err := os.ReadFile(assFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ass := string(data)
+	for _, want := range []string{
+		"PlayResX: 1080\nPlayResY: 1920\n",
+		"Style: Default,Arial Black,80,&H0000D4FF,&H00FFFFFF,&H00000000,&H80000000,-1,0,0,0,100,100,0,0,1,5,2,2,60,60,480,1\n",
+		// The pause after "(the)" stays on that word, and the second line
+		// starts with a hard break
+		"Dialogue: 0,0:00:00.00,0:00:01.25,Default,,0,0,0,,{\\k30}Meet {\\k40}(the)\\N{\\k55}robot.\n",
+	} {
+		if !strings.Contains(ass, want) {
+			t.Errorf("ASS missing %q:\n%s", want, ass)
+		}
+	}
+
+	cfg.CaptionHighlightColor = "yellow"
+	if err := service.WriteCaptions(cues, "ass", assFile); err == nil {
+		t.Error("expected error for invalid highlight colour")
+	}
+}
@@ -0,0 +1,131 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// backgroundColor fills the frame when no images are available
+const backgroundColor = "#0f0f23"
+
+// frameSize returns the output dimensions, defaulting to vertical 1080p
+func (s *Service) frameSize() (int, int) {
+	if s.config.VideoWidth <= 0 || s.config.VideoHeight <= 0 {
+		return 1080, 1920
+	}
+	return s.config.VideoWidth, s.config.VideoHeight
+}
+
+// encodeArgs returns the H.264 encoder settings shared by every render
+func (s *Service) encodeArgs() []string {
+	preset := s.config.VideoPreset
+	if preset == "" {
+		preset = "veryfast"
+	}
+	crf := s.config.VideoCRF
+	if crf <= 0 {
+		crf = 18
+	}
+	args := []string{"-c:v", "libx264", "-preset", preset, "-crf", strconv.Itoa(crf), "-pix_fmt", "yuv420p"}
+	if rate := s.config.VideoMaxRate; rate != "" {
+		args = append(args, "-maxrate", rate, "-bufsize", rate)
+	}
+	return args
+}
+
+// audioEncodeArgs returns the AAC encoder settings for the final mix
+func (s *Service) audioEncodeArgs() []string {
+	bitrate := s.config.AudioBitrate
+	if bitrate == "" {
+		bitrate = "192k"
+	}
+	return []string{"-c:a", "aac", "-b:a", bitrate}
+}
+
+// fillFilter scales and crops the input to cover the whole frame
+func (s *Service) fillFilter() string {
+	w, h := s.frameSize()
+	return fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d", w, h, w, h)
+}
+
+// imageClipArgs renders a still image as a clip with Ken Burns motion
+func (s *Service) imageClipArgs(image, outPath string, duration time.Duration, motion Motion) []string {
+	args := []string{"-y",
+		"-i", image,
+		"-t", seconds(duration),
+		"-vf", s.kenBurnsFilter(motion, duration),
+	}
+	args = append(args, s.encodeArgs()...)
+	return append(args, outPath)
+}
+
+// videoClipArgs renders a B-roll clip at the output size and frame rate
+// without its audio. Clips longer than the segment play their middle part;
+// shorter ones loop.
+func (s *Service) videoClipArgs(clip, outPath string, duration, clipLength time.Duration) []string {
+	args := []string{"-y"}
+	if clipLength > duration {
+		args = append(args, "-ss", seconds((clipLength-duration)/2))
+	}
+	args = append(args,
+		"-stream_loop", "-1", "-i", clip,
+		"-t", seconds(duration),
+		"-vf", fmt.Sprintf("%s,setsar=1,fps=%d", s.fillFilter(), s.fps()),
+		"-an",
+	)
+	args = append(args, s.encodeArgs()...)
+	return append(args, outPath)
+}
+
+// colorClipArgs renders a plain colour clip
+func (s *Service) colorClipArgs(outPath string, duration time.Duration) []string {
+	w, h := s.frameSize()
+	args := []string{"-y",
+		"-f", "lavfi", "-t", seconds(duration),
+		"-i", fmt.Sprintf("color=c=%s:s=%dx%d:r=%d", backgroundColor, w, h, s.fps()),
+	}
+	args = append(args, s.encodeArgs()...)
+	return append(args, outPath)
+}
+
+// subtitleArgs burns captions into the video, matching the frame size
+func (s *Service) subtitleArgs(video, subtitleFilter, outPath string) []string {
+	args := []string{"-y",
+		"-i", video,
+		"-vf", s.fillFilter() + "," + subtitleFilter,
+	}
+	args = append(args, s.encodeArgs()...)
+	return append(args, outPath)
+}
+
+// logoArgs overlays the logo in the top right corner
+func (s *Service) logoArgs(video, logo, outPath string) []string {
+	args := []string{"-y",
+		"-i", video,
+		"-i", logo,
+		"-filter_complex", "[0:v][1:v]" + s.overlayFilter(),
+	}
+	args = append(args, s.encodeArgs()...)
+	return append(args, outPath)
+}
+
+// overlayFilter places the logo in the top right corner, LogoMargin from the edges
+func (s *Service) overlayFilter() string {
+	margin := s.config.LogoMargin
+	if margin < 0 {
+		margin = 0
+	}
+	return fmt.Sprintf("overlay=W-w-%d:%d", margin, margin)
+}
+
+func (s *Service) runFFmpeg(ctx context.Context, args []string) error {
+	return exec.CommandContext(ctx, "ffmpeg", args...).Run()
+}
+
+// seconds formats a duration for ffmpeg's -t option
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
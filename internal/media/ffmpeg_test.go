@@ -0,0 +1,54 @@
+package media
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+	"github.com/g-laliotis/convertbox/internal/logger"
+)
+
+func TestService_FFmpegArgsFollowConfig(t *testing.T) {
+	cfg := &config.Config{
+		VideoWidth:  720,
+		VideoHeight: 720,
+		VideoCRF:    28,
+		VideoPreset: "medium",
+		LogoMargin:  33,
+	}
+	service := NewService(cfg, logger.New())
+
+	invocations := map[string][]string{
+		"image clip": service.imageClipArgs("in.jpg", "out.mp4", 2500*time.Millisecond, Motion{Start: Rect{W: 1, H: 1}, End: Rect{W: 1, H: 1}}),
+		"color clip": service.colorClipArgs("out.mp4", 5*time.Second),
+		"video clip": service.videoClipArgs("in.mp4", "out.mp4", 2*time.Second, 8*time.Second),
+		"subtitles":  service.subtitleArgs("in.mp4", "ass=subs.ass", "out.mp4"),
+		"logo":       service.logoArgs("in.mp4", "logo.png", "out.mp4"),
+	}
+
+	for name, args := range invocations {
+		joined := strings.Join(args, " ")
+		for _, hardcoded := range []string{"1080", "1920", "1280", "ultrafast", "-preset fast", "-crf 20"} {
+			if strings.Contains(joined, hardcoded) {
+				t.Errorf("%s args contain %q: %s", name, hardcoded, joined)
+			}
+		}
+		if !strings.Contains(joined, "-preset medium -crf 28") {
+			t.Errorf("%s args ignore VIDEO_PRESET/VIDEO_CRF: %s", name, joined)
+		}
+		if name != "logo" && !strings.Contains(joined, "720") {
+			t.Errorf("%s args don't use the configured frame size: %s", name, joined)
+		}
+	}
+
+	if joined := strings.Join(invocations["image clip"], " "); !strings.Contains(joined, "crop=1440:1440") || !strings.Contains(joined, "s=720x720") || !strings.Contains(joined, "-t 2.500") {
+		t.Errorf("image clip args = %s", joined)
+	}
+	if joined := strings.Join(invocations["video clip"], " "); !strings.Contains(joined, "-ss 3.000 -stream_loop -1 -i in.mp4 -t 2.000") || !strings.Contains(joined, "crop=720:720,setsar=1,fps=30") || !strings.Contains(joined, "-an") {
+		t.Errorf("video clip args = %s", joined)
+	}
+	if joined := strings.Join(invocations["logo"], " "); !strings.Contains(joined, "overlay=W-w-33:33") {
+		t.Errorf("logo args ignore LOGO_MARGIN: %s", joined)
+	}
+}
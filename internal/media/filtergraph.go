@@ -0,0 +1,71 @@
+package media
+
+import (
+	"fmt"
+	"strings"
+)
+
+// filterGraph composes ffmpeg inputs and a -filter_complex graph so a whole
+// render runs as one ffmpeg invocation
+type filterGraph struct {
+	inputs [][]string
+	chains []string
+}
+
+// input adds an input with its options and returns the input index
+func (g *filterGraph) input(args ...string) int {
+	g.inputs = append(g.inputs, args)
+	return len(g.inputs) - 1
+}
+
+// chain adds a filter chain reading the in labels and writing the out labels
+func (g *filterGraph) chain(in []string, filters string, out ...string) {
+	var chain strings.Builder
+	for _, label := range in {
+		fmt.Fprintf(&chain, "[%s]", label)
+	}
+	chain.WriteString(filters)
+	for _, label := range out {
+		fmt.Fprintf(&chain, "[%s]", label)
+	}
+	g.chains = append(g.chains, chain.String())
+}
+
+// args returns the input arguments followed by the filter graph
+func (g *filterGraph) args() []string {
+	var args []string
+	for _, input := range g.inputs {
+		args = append(args, input...)
+	}
+	if len(g.chains) > 0 {
+		args = append(args, "-filter_complex", strings.Join(g.chains, ";"))
+	}
+	return args
+}
+
+func stream(index int, kind string) string {
+	return fmt.Sprintf("%d:%s", index, kind)
+}
+
+// renderArgs builds the single-pass render: captions and logo are drawn
+// over the background and the audio mixed in one filter graph, so the video
+// is encoded only once
+func (s *Service) renderArgs(cfg RenderConfig) []string {
+	g := &filterGraph{}
+	video := g.input("-i", cfg.VideoInputs[0])
+	audio := s.mixAudio(g, cfg, s.renderAudioFilter(cfg))
+
+	g.chain([]string{stream(video, "v")}, s.fillFilter()+","+cfg.subtitleFilter(), "captioned")
+	videoOut := "captioned"
+	if cfg.Logo != "" {
+		logo := g.input("-i", cfg.Logo)
+		g.chain([]string{videoOut, stream(logo, "v")}, s.overlayFilter(), "branded")
+		videoOut = "branded"
+	}
+
+	args := append([]string{"-y"}, g.args()...)
+	args = append(args, "-map", "["+videoOut+"]", "-map", "["+audio+"]")
+	args = append(args, s.encodeArgs()...)
+	args = append(args, s.audioEncodeArgs()...)
+	return append(args, "-shortest", cfg.Output)
+}
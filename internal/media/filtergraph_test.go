@@ -0,0 +1,98 @@
+package media
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+	"github.com/g-laliotis/convertbox/internal/logger"
+)
+
+func TestService_RenderArgs(t *testing.T) {
+	cfg := &config.Config{
+		VideoWidth:       1080,
+		VideoHeight:      1920,
+		VideoCRF:         18,
+		VideoPreset:      "veryfast",
+		LogoMargin:       40,
+		LoudnessTarget:   -14,
+		LoudnessTruePeak: -1.5,
+		LoudnessRange:    11,
+		MusicLevelDB:     -12,
+		MusicFadeIn:      1.5,
+		MusicFadeOut:     2.5,
+		DuckThreshold:    0.05,
+		DuckRatio:        8,
+		DuckAttackMs:     20,
+		DuckReleaseMs:    400,
+	}
+	service := NewService(cfg, logger.New())
+
+	args := service.renderArgs(RenderConfig{
+		VideoInputs: []string{"bg.mp4"},
+		Narration:   "narration.wav",
+		Music:       "music.mp3",
+		Logo:        "logo.png",
+		CaptionsSRT: "subs.srt",
+		CaptionsASS: "subs.ass",
+		Output:      "final.mp4",
+		Duration:    42 * time.Second,
+	})
+	joined := strings.Join(args, " ")
+
+	if strings.Count(joined, "-filter_complex") != 1 || strings.Count(joined, "libx264") != 1 {
+		t.Fatalf("expected one filter graph and one video encode: %s", joined)
+	}
+	wantGraph := "[1:a]asplit=2[narr][voice];[voice]apad[key];" +
+		"[2:a]volume=-12dB,afade=t=in:st=0:d=1.5,afade=t=out:st=39.500:d=2.5,atrim=end=42.000[bed];" +
+		"[bed][key]sidechaincompress=threshold=0.05:ratio=8:attack=20:release=400[ducked];" +
+		"[narr][ducked]amix=inputs=2:duration=longest,loudnorm=I=-14:TP=-1.5:LRA=11,aresample=48000,apad[mixed];" +
+		"[0:v]scale=1080:1920:force_original_aspect_ratio=increase,crop=1080:1920,ass=subs.ass[captioned];" +
+		"[captioned][3:v]overlay=W-w-40:40[branded]"
+	if !strings.Contains(joined, "-filter_complex "+wantGraph+" ") {
+		t.Errorf("filter graph mismatch:\n%s\nwant\n%s", joined, wantGraph)
+	}
+	if !strings.Contains(joined, "-i bg.mp4 -i narration.wav -stream_loop -1 -i music.mp3 -i logo.png") {
+		t.Errorf("inputs out of order: %s", joined)
+	}
+	if !strings.Contains(joined, "-map [branded] -map [mixed]") || !strings.HasSuffix(joined, "-shortest final.mp4") {
+		t.Errorf("outputs not mapped: %s", joined)
+	}
+}
+
+func TestService_RenderArgsMinimal(t *testing.T) {
+	service := NewService(&config.Config{}, logger.New())
+
+	joined := strings.Join(service.renderArgs(RenderConfig{
+		VideoInputs: []string{"bg.mp4"},
+		Narration:   "narration.wav",
+		CaptionsSRT: "subs.srt",
+		Output:      "final.mp4",
+	}), " ")
+
+	if !strings.Contains(joined, "-filter_complex [1:a]apad[mixed];[0:v]scale=1080:1920:force_original_aspect_ratio=increase,crop=1080:1920,subtitles=subs.srt[captioned] ") {
+		t.Errorf("unexpected filter graph: %s", joined)
+	}
+	if !strings.Contains(joined, "-map [captioned] -map [mixed]") {
+		t.Errorf("outputs not mapped: %s", joined)
+	}
+}
+
+func TestService_MusicBedFilter(t *testing.T) {
+	service := NewService(&config.Config{MusicLevelDB: -18, MusicFadeIn: 1, MusicFadeOut: 3}, logger.New())
+
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{0, "volume=-18dB,afade=t=in:st=0:d=1"},
+		{2 * time.Second, "volume=-18dB,afade=t=in:st=0:d=1,afade=t=out:st=0.000:d=2,atrim=end=2.000"},
+		{10 * time.Second, "volume=-18dB,afade=t=in:st=0:d=1,afade=t=out:st=7.000:d=3,atrim=end=10.000"},
+	}
+	for _, tt := range tests {
+		if got := service.musicBedFilter(tt.duration); got != tt.want {
+			t.Errorf("musicBedFilter(%v) = %q, want %q", tt.duration, got, tt.want)
+		}
+	}
+}
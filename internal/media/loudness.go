@@ -0,0 +1,93 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// LoudnessStats are the EBU R128 measurements loudnorm reports for the mix
+type LoudnessStats struct {
+	InputI       float64 // integrated loudness, LUFS
+	InputTP      float64 // true peak, dBTP
+	InputLRA     float64 // loudness range, LU
+	InputThresh  float64
+	TargetOffset float64
+}
+
+// loudnormParams returns the target options shared by both loudnorm passes
+func (s *Service) loudnormParams() string {
+	return fmt.Sprintf("I=%g:TP=%g:LRA=%g", s.config.LoudnessTarget, s.config.LoudnessTruePeak, s.config.LoudnessRange)
+}
+
+// loudnessFilter normalizes the final mix to the configured target, or
+// returns "" when normalization is disabled. With measurements from a first
+// pass loudnorm applies a single linear gain; without them it falls back to
+// dynamic one-pass normalization. loudnorm upsamples to 192kHz, so the
+// result is resampled back to 48kHz.
+func (s *Service) loudnessFilter(stats *LoudnessStats) string {
+	if s.config.LoudnessTarget == 0 {
+		return ""
+	}
+	if stats == nil {
+		return fmt.Sprintf("loudnorm=%s,aresample=48000", s.loudnormParams())
+	}
+	return fmt.Sprintf("loudnorm=%s:measured_I=%.2f:measured_TP=%.2f:measured_LRA=%.2f:measured_thresh=%.2f:offset=%.2f:linear=true,aresample=48000",
+		s.loudnormParams(), stats.InputI, stats.InputTP, stats.InputLRA, stats.InputThresh, stats.TargetOffset)
+}
+
+// measureArgs mixes the audio exactly as the render does and runs the first
+// loudnorm pass over it, discarding the output
+func (s *Service) measureArgs(cfg RenderConfig) []string {
+	g := &filterGraph{}
+	mixed := s.mixAudio(g, cfg, fmt.Sprintf("loudnorm=%s:print_format=json", s.loudnormParams()))
+
+	args := append([]string{"-hide_banner", "-nostats"}, g.args()...)
+	return append(args, "-map", "["+mixed+"]", "-f", "null", "-")
+}
+
+// measureLoudness runs the measurement pass of two-pass loudnorm
+func (s *Service) measureLoudness(ctx context.Context, cfg RenderConfig) (*LoudnessStats, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "ffmpeg", s.measureArgs(cfg)...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("loudness measurement failed: %w", err)
+	}
+	return parseLoudnorm(stderr.Bytes())
+}
+
+// parseLoudnorm reads the JSON block loudnorm prints at the end of its log
+func parseLoudnorm(output []byte) (*LoudnessStats, error) {
+	start := bytes.LastIndexByte(output, '{')
+	end := bytes.LastIndexByte(output, '}')
+	if start < 0 || end < start {
+		return nil, errors.New("no loudnorm measurements in ffmpeg output")
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal(output[start:end+1], &raw); err != nil {
+		return nil, fmt.Errorf("parse loudnorm measurements: %w", err)
+	}
+
+	var stats LoudnessStats
+	fields := map[string]*float64{
+		"input_i":       &stats.InputI,
+		"input_tp":      &stats.InputTP,
+		"input_lra":     &stats.InputLRA,
+		"input_thresh":  &stats.InputThresh,
+		"target_offset": &stats.TargetOffset,
+	}
+	for key, field := range fields {
+		value, err := strconv.ParseFloat(raw[key], 64)
+		if err != nil {
+			return nil, fmt.Errorf("loudnorm %s: %q is not a number", key, raw[key])
+		}
+		*field = value
+	}
+	return &stats, nil
+}
@@ -0,0 +1,68 @@
+package media
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+	"github.com/g-laliotis/convertbox/internal/logger"
+)
+
+// loudnormLog is the tail of an ffmpeg run with loudnorm=print_format=json
+const loudnormLog = `[Parsed_loudnorm_0 @ 0x55d4c8a0f2c0]
+{
+	"input_i" : "-23.41",
+	"input_tp" : "-4.02",
+	"input_lra" : "6.30",
+	"input_thresh" : "-33.85",
+	"output_i" : "-14.03",
+	"output_tp" : "-1.50",
+	"output_lra" : "5.10",
+	"output_thresh" : "-24.40",
+	"normalization_type" : "dynamic",
+	"target_offset" : "0.03"
+}
+`
+
+func TestParseLoudnorm(t *testing.T) {
+	stats, err := parseLoudnorm([]byte("size=N/A time=00:00:42.00 bitrate=N/A\n" + loudnormLog))
+	if err != nil {
+		t.Fatalf("parseLoudnorm failed: %v", err)
+	}
+	want := LoudnessStats{InputI: -23.41, InputTP: -4.02, InputLRA: 6.3, InputThresh: -33.85, TargetOffset: 0.03}
+	if *stats != want {
+		t.Errorf("stats = %+v, want %+v", *stats, want)
+	}
+
+	if _, err := parseLoudnorm([]byte("Error opening input file")); err == nil {
+		t.Error("expected error without measurements")
+	}
+	if _, err := parseLoudnorm([]byte(`{"input_i" : "-inf"}`)); err == nil {
+		t.Error("expected error for incomplete measurements")
+	}
+}
+
+func TestService_LoudnessFilter(t *testing.T) {
+	cfg := &config.Config{LoudnessTarget: -14, LoudnessTruePeak: -1, LoudnessRange: 11}
+	service := NewService(cfg, logger.New())
+
+	if got, want := service.loudnessFilter(nil), "loudnorm=I=-14:TP=-1:LRA=11,aresample=48000"; got != want {
+		t.Errorf("one-pass filter = %q, want %q", got, want)
+	}
+
+	stats := &LoudnessStats{InputI: -23.41, InputTP: -4.02, InputLRA: 6.3, InputThresh: -33.85, TargetOffset: 0.03}
+	want := "loudnorm=I=-14:TP=-1:LRA=11:measured_I=-23.41:measured_TP=-4.02:measured_LRA=6.30:measured_thresh=-33.85:offset=0.03:linear=true,aresample=48000"
+	if got := service.loudnessFilter(stats); got != want {
+		t.Errorf("two-pass filter = %q, want %q", got, want)
+	}
+
+	measure := strings.Join(service.measureArgs(RenderConfig{Narration: "narration.wav"}), " ")
+	if !strings.Contains(measure, "[0:a]loudnorm=I=-14:TP=-1:LRA=11:print_format=json[mixed]") || !strings.HasSuffix(measure, "-f null -") {
+		t.Errorf("measure args = %s", measure)
+	}
+
+	cfg.LoudnessTarget = 0
+	if got := service.loudnessFilter(stats); got != "" {
+		t.Errorf("disabled normalization returned %q", got)
+	}
+}
@@ -0,0 +1,118 @@
+package media
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// motionOversample renders zoompan from an image this many times the frame
+// size, so the crop moves in sub-pixel steps instead of jittering
+const motionOversample = 2
+
+// Rect is a region of the image as fractions of its width and height. Rects
+// keep the frame's aspect ratio, so W and H are equal.
+type Rect struct {
+	X, Y, W, H float64
+}
+
+// Motion is a Ken Burns move from one region of the image to another
+type Motion struct {
+	Kind   string
+	Start  Rect
+	End    Rect
+	Easing string
+}
+
+var (
+	motionKinds = []string{"zoom-in", "zoom-out", "pan-left", "pan-right", "pan-up", "pan-down"}
+	easings     = []string{"linear", "ease-in-out", "ease-out"}
+)
+
+// newMotion picks a move at random. Both rects lie inside the image, so no
+// point along the way shows past its edge.
+func newMotion(random *rand.Rand) Motion {
+	kind := motionKinds[random.Intn(len(motionKinds))]
+	easing := easings[random.Intn(len(easings))]
+
+	// The cropped region covers 75-85% of the image
+	size := 0.75 + random.Float64()*0.1
+	span := 1 - size
+	full := Rect{X: 0, Y: 0, W: 1, H: 1}
+	inner := Rect{X: random.Float64() * span, Y: random.Float64() * span, W: size, H: size}
+
+	motion := Motion{Kind: kind, Easing: easing}
+	switch kind {
+	case "zoom-in":
+		motion.Start, motion.End = full, inner
+	case "zoom-out":
+		motion.Start, motion.End = inner, full
+	case "pan-left", "pan-right":
+		motion.Start, motion.End = inner, inner
+		motion.Start.X, motion.End.X = span, 0
+		if kind == "pan-right" {
+			motion.Start.X, motion.End.X = 0, span
+		}
+	case "pan-up", "pan-down":
+		motion.Start, motion.End = inner, inner
+		motion.Start.Y, motion.End.Y = span, 0
+		if kind == "pan-down" {
+			motion.Start.Y, motion.End.Y = 0, span
+		}
+	}
+	return motion
+}
+
+// easingExpr returns an ffmpeg expression easing progress p from 0 to 1
+func easingExpr(easing, p string) string {
+	switch easing {
+	case "ease-in-out":
+		return fmt.Sprintf("(%s*%s*(3-2*%s))", p, p, p)
+	case "ease-out":
+		return fmt.Sprintf("(1-(1-%s)*(1-%s))", p, p)
+	default:
+		return p
+	}
+}
+
+// fps returns the configured frame rate
+func (s *Service) fps() int {
+	if s.config.VideoFPS <= 0 {
+		return 30
+	}
+	return s.config.VideoFPS
+}
+
+// kenBurnsFilter renders the motion over exactly the frames of the clip.
+// zoompan emits every frame from the single input image, so the progress is
+// the output frame number over the frame count.
+func (s *Service) kenBurnsFilter(motion Motion, duration time.Duration) string {
+	w, h := s.frameSize()
+	fps := s.fps()
+	frames := int(math.Round(duration.Seconds() * float64(fps)))
+	if frames < 2 {
+		frames = 2
+	}
+
+	e := easingExpr(motion.Easing, fmt.Sprintf("(on/%d)", frames-1))
+	lerp := func(from, to float64) string {
+		return fmt.Sprintf("(%.4f+%.4f*%s)", from, to-from, e)
+	}
+
+	return fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d,"+
+		"zoompan=z='1/%s':x='iw*%s':y='ih*%s':d=%d:s=%dx%d:fps=%d",
+		w*motionOversample, h*motionOversample, w*motionOversample, h*motionOversample,
+		lerp(motion.Start.W, motion.End.W), lerp(motion.Start.X, motion.End.X), lerp(motion.Start.Y, motion.End.Y),
+		frames, w, h, fps)
+}
+
+// motionRandom returns the source for motions and transitions; a fixed
+// BACKGROUND_SEED makes the backgrounds reproducible
+func (s *Service) motionRandom() *rand.Rand {
+	seed := s.config.BackgroundSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
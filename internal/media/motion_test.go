@@ -0,0 +1,59 @@
+package media
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+	"github.com/g-laliotis/convertbox/internal/logger"
+)
+
+func TestNewMotion_StaysInsideImage(t *testing.T) {
+	kinds := map[string]bool{}
+	for seed := int64(0); seed < 200; seed++ {
+		motion := newMotion(rand.New(rand.NewSource(seed)))
+		kinds[motion.Kind] = true
+		for _, rect := range []Rect{motion.Start, motion.End} {
+			if rect.W != rect.H || rect.W < 0.75 || rect.W > 1 {
+				t.Fatalf("seed %d: rect %+v changes the aspect ratio or zooms too far", seed, rect)
+			}
+			if rect.X < 0 || rect.Y < 0 || rect.X+rect.W > 1+1e-9 || rect.Y+rect.H > 1+1e-9 {
+				t.Fatalf("seed %d: rect %+v reveals the image edge", seed, rect)
+			}
+		}
+		if motion.Start == motion.End {
+			t.Fatalf("seed %d: %s motion doesn't move", seed, motion.Kind)
+		}
+	}
+	if len(kinds) != len(motionKinds) {
+		t.Errorf("only picked %v", kinds)
+	}
+}
+
+func TestNewMotion_Reproducible(t *testing.T) {
+	a := newMotion(rand.New(rand.NewSource(42)))
+	b := newMotion(rand.New(rand.NewSource(42)))
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("same seed gave %+v and %+v", a, b)
+	}
+}
+
+func TestService_KenBurnsFilter(t *testing.T) {
+	service := NewService(&config.Config{VideoWidth: 1080, VideoHeight: 1920, VideoFPS: 25}, logger.New())
+	motion := Motion{Start: Rect{W: 1, H: 1}, End: Rect{X: 0.1, Y: 0.05, W: 0.8, H: 0.8}, Easing: "ease-in-out"}
+
+	filter := service.kenBurnsFilter(motion, 4*time.Second)
+	for _, want := range []string{
+		"crop=2160:3840",
+		"z='1/(1.0000+-0.2000*",
+		"(on/99)",
+		"d=100:s=1080x1920:fps=25",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("filter missing %q: %s", want, filter)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package media
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+)
+
+// Profile describes the output format one platform expects
+type Profile struct {
+	Name           string
+	Width          int
+	Height         int
+	Aspect         string
+	MaxDuration    time.Duration // 0 means no limit
+	VideoMaxRate   string        // ffmpeg bitrate cap, e.g. "8M"
+	AudioBitrate   string
+	LoudnessTarget float64 // integrated loudness in LUFS
+	CaptionMargin  int     // keeps captions above the platform's bottom UI
+	LogoMargin     int     // keeps the logo clear of the platform's top UI
+}
+
+var profiles = map[string]Profile{
+	"shorts": {
+		Name: "shorts", Width: 1080, Height: 1920, Aspect: "9:16",
+		MaxDuration: 60 * time.Second, VideoMaxRate: "10M", AudioBitrate: "192k",
+		LoudnessTarget: -14, CaptionMargin: 480, LogoMargin: 40,
+	},
+	"tiktok": {
+		Name: "tiktok", Width: 1080, Height: 1920, Aspect: "9:16",
+		MaxDuration: 10 * time.Minute, VideoMaxRate: "8M", AudioBitrate: "192k",
+		LoudnessTarget: -14, CaptionMargin: 560, LogoMargin: 160,
+	},
+	"reels": {
+		Name: "reels", Width: 1080, Height: 1920, Aspect: "9:16",
+		MaxDuration: 90 * time.Second, VideoMaxRate: "8M", AudioBitrate: "192k",
+		LoudnessTarget: -14, CaptionMargin: 520, LogoMargin: 120,
+	},
+	"youtube": {
+		Name: "youtube", Width: 1920, Height: 1080, Aspect: "16:9",
+		VideoMaxRate: "12M", AudioBitrate: "192k",
+		LoudnessTarget: -14, CaptionMargin: 80, LogoMargin: 40,
+	},
+}
+
+// Profiles returns the names of the built-in output profiles
+func Profiles() []string {
+	var names []string
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupProfile returns the named output profile
+func LookupProfile(name string) (Profile, error) {
+	profile, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(Profiles(), ", "))
+	}
+	return profile, nil
+}
+
+// Apply returns a copy of cfg with the profile's output settings, for a
+// media service that renders this profile
+func (p Profile) Apply(cfg *config.Config) *config.Config {
+	profiled := *cfg
+	profiled.VideoWidth = p.Width
+	profiled.VideoHeight = p.Height
+	profiled.VideoMaxRate = p.VideoMaxRate
+	profiled.AudioBitrate = p.AudioBitrate
+	profiled.LoudnessTarget = p.LoudnessTarget
+	profiled.CaptionMargin = p.CaptionMargin
+	profiled.LogoMargin = p.LogoMargin
+	return &profiled
+}
+
+// Fits reports whether a video of the given length is allowed on the platform
+func (p Profile) Fits(duration time.Duration) bool {
+	return p.MaxDuration == 0 || duration <= p.MaxDuration
+}
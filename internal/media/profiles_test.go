@@ -0,0 +1,59 @@
+package media
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+	"github.com/g-laliotis/convertbox/internal/logger"
+)
+
+func TestLookupProfile(t *testing.T) {
+	for _, name := range Profiles() {
+		profile, err := LookupProfile(name)
+		if err != nil {
+			t.Fatalf("LookupProfile(%q) failed: %v", name, err)
+		}
+		if profile.Width <= 0 || profile.Height <= 0 || profile.LoudnessTarget >= 0 {
+			t.Errorf("profile %q is incomplete: %+v", name, profile)
+		}
+	}
+
+	if _, err := LookupProfile("Shorts"); err != nil {
+		t.Errorf("profile names should be case-insensitive: %v", err)
+	}
+	if _, err := LookupProfile("vine"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+func TestProfile_Apply(t *testing.T) {
+	cfg := &config.Config{VideoWidth: 720, VideoHeight: 1280, VideoCRF: 23, CaptionMargin: 100}
+	profile, _ := LookupProfile("youtube")
+
+	profiled := profile.Apply(cfg)
+	if cfg.VideoWidth != 720 || cfg.CaptionMargin != 100 {
+		t.Error("Apply modified the original config")
+	}
+	if profiled.VideoWidth != 1920 || profiled.VideoHeight != 1080 || profiled.VideoCRF != 23 {
+		t.Errorf("profiled config = %+v", profiled)
+	}
+
+	joined := strings.Join(NewService(profiled, logger.New()).colorClipArgs("out.mp4", time.Second), " ")
+	if !strings.Contains(joined, "s=1920x1080") || !strings.Contains(joined, "-maxrate 12M") {
+		t.Errorf("profile not used in ffmpeg args: %s", joined)
+	}
+}
+
+func TestProfile_Fits(t *testing.T) {
+	shorts, _ := LookupProfile("shorts")
+	youtube, _ := LookupProfile("youtube")
+
+	if !shorts.Fits(59*time.Second) || shorts.Fits(61*time.Second) {
+		t.Error("shorts should allow up to 60 seconds")
+	}
+	if !youtube.Fits(time.Hour) {
+		t.Error("youtube has no length limit")
+	}
+}
@@ -0,0 +1,177 @@
+package media
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/g-laliotis/convertbox/internal/align"
+	"github.com/g-laliotis/convertbox/internal/textnorm"
+)
+
+// Segmenter splits timed words into caption cues that fit on screen and can
+// be read in time
+type Segmenter struct {
+	MaxCharsPerLine   int
+	MaxLines          int
+	MinDuration       time.Duration
+	MaxDuration       time.Duration
+	MaxCharsPerSecond float64
+}
+
+// phraseWords are poor places to end a line, since they belong with the word after them
+var phraseWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "but": true,
+	"of": true, "to": true, "in": true, "on": true, "at": true, "for": true,
+	"with": true, "by": true, "from": true, "your": true, "my": true, "our": true,
+	"is": true, "are": true, "was": true, "it's": true, "that": true,
+}
+
+// Segment builds cues from the words of each script segment; no cue spans
+// two segments. Cues break at sentence ends, prefer breaking after commas
+// and similar punctuation, and stay on screen long enough to read.
+func (sg Segmenter) Segment(segments [][]align.Word) []Cue {
+	var cues []Cue
+	for _, words := range segments {
+		var current []align.Word
+		for _, word := range words {
+			if len(current) > 0 && !sg.fits(append(current, word)) {
+				keep, carry := sg.breakPoint(current, word)
+				cues = append(cues, sg.cue(keep))
+				current = append([]align.Word(nil), carry...)
+			}
+			current = append(current, word)
+			if textnorm.EndsSentence(word.Text) {
+				cues = append(cues, sg.cue(current))
+				current = nil
+			}
+		}
+		if len(current) > 0 {
+			cues = append(cues, sg.cue(current))
+		}
+	}
+
+	sg.extend(cues)
+	return cues
+}
+
+// fits reports whether the words fit the line and duration limits as one cue
+func (sg Segmenter) fits(words []align.Word) bool {
+	if sg.MaxDuration > 0 && words[len(words)-1].End-words[0].Start > sg.MaxDuration {
+		return false
+	}
+	return len(layoutLines(words, sg.MaxCharsPerLine)) <= sg.maxLines()
+}
+
+func (sg Segmenter) maxLines() int {
+	if sg.MaxLines < 1 {
+		return 1
+	}
+	return sg.MaxLines
+}
+
+func (sg Segmenter) cue(words []align.Word) Cue {
+	return Cue{
+		Start: words[0].Start,
+		End:   words[len(words)-1].End,
+		Words: words,
+		Lines: balanceLines(words, sg.MaxCharsPerLine),
+	}
+}
+
+// extend keeps each cue up for its minimum and reading time, without
+// overlapping the next cue or exceeding the maximum duration
+func (sg Segmenter) extend(cues []Cue) {
+	for i := range cues {
+		cue := &cues[i]
+		want := sg.MinDuration
+		if sg.MaxCharsPerSecond > 0 {
+			chars := utf8.RuneCountInString(strings.Join(cue.Lines, " "))
+			if reading := time.Duration(float64(chars) / sg.MaxCharsPerSecond * float64(time.Second)); reading > want {
+				want = reading
+			}
+		}
+		if sg.MaxDuration > 0 && want > sg.MaxDuration {
+			want = sg.MaxDuration
+		}
+
+		end := cue.Start + want
+		if i+1 < len(cues) && end > cues[i+1].Start {
+			end = cues[i+1].Start
+		}
+		if end > cue.End {
+			cue.End = end
+		}
+	}
+}
+
+// breakPoint splits a full cue, moving words after the last punctuation
+// break, or a dangling phrase word, over to the next cue when they fit
+// there with the next word
+func (sg Segmenter) breakPoint(words []align.Word, next align.Word) (keep, carry []align.Word) {
+	carries := func(i int) bool {
+		rest := append(append([]align.Word(nil), words[i:]...), next)
+		return sg.fits(rest)
+	}
+
+	for i := len(words) - 2; i >= (len(words)-1)/2; i-- {
+		if endsClause(words[i].Text) && carries(i+1) {
+			return words[:i+1], words[i+1:]
+		}
+	}
+	last := len(words) - 1
+	if last > 1 && phraseWords[strings.ToLower(words[last].Text)] && carries(last) {
+		return words[:last], words[last:]
+	}
+	return words, nil
+}
+
+func endsClause(word string) bool {
+	word = strings.TrimRight(word, "\"')]”’")
+	return strings.HasSuffix(word, ",") || strings.HasSuffix(word, ";") ||
+		strings.HasSuffix(word, ":") || strings.HasSuffix(word, "—") || word == "-"
+}
+
+// layoutLines fills lines greedily up to maxChars; a word longer than a line
+// gets a line of its own
+func layoutLines(words []align.Word, maxChars int) []string {
+	var lines []string
+	var line string
+	for _, word := range words {
+		switch {
+		case line == "":
+			line = word.Text
+		case maxChars > 0 && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word.Text) > maxChars:
+			lines = append(lines, line)
+			line = word.Text
+		default:
+			line += " " + word.Text
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// balanceLines lays the words out in as few lines as possible, narrowing the
+// line width so the lines come out similar in length
+func balanceLines(words []align.Word, maxChars int) []string {
+	lines := layoutLines(words, maxChars)
+	if len(lines) < 2 {
+		return lines
+	}
+
+	total := 0
+	for _, word := range words {
+		total += utf8.RuneCountInString(word.Text)
+	}
+	total += len(words) - 1
+
+	for width := (total + len(lines) - 1) / len(lines); width < maxChars; width++ {
+		if balanced := layoutLines(words, width); len(balanced) <= len(lines) {
+			return balanced
+		}
+	}
+	return lines
+}
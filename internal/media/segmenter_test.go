@@ -0,0 +1,128 @@
+package media
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/align"
+)
+
+// timedWords spaces the words of text step apart, starting at start
+func timedWords(text string, start, step time.Duration) []align.Word {
+	var words []align.Word
+	for i, field := range strings.Fields(text) {
+		at := start + time.Duration(i)*step
+		words = append(words, align.Word{Text: field, Start: at, End: at + step})
+	}
+	return words
+}
+
+func cueTexts(cues []Cue) []string {
+	var texts []string
+	for _, cue := range cues {
+		texts = append(texts, cue.Text())
+	}
+	return texts
+}
+
+func TestSegmenter_Segment(t *testing.T) {
+	step := 300 * time.Millisecond
+	tests := []struct {
+		name      string
+		segmenter Segmenter
+		segments  []string
+		want      []string
+	}{
+		{
+			name:      "initialism stays with its noun",
+			segmenter: Segmenter{MaxCharsPerLine: 18, MaxLines: 2},
+			segments:  []string{"A.I. tools write code."},
+			want:      []string{"A.I. tools\nwrite code."},
+		},
+		{
+			name:      "sentence ends break cues",
+			segmenter: Segmenter{MaxCharsPerLine: 40, MaxLines: 2},
+			segments:  []string{"Hi there. Go now!"},
+			want:      []string{"Hi there.", "Go now!"},
+		},
+		{
+			name:      "prefers breaking at punctuation",
+			segmenter: Segmenter{MaxCharsPerLine: 24, MaxLines: 1},
+			segments:  []string{"Fast, cheap, and private tools win"},
+			want:      []string{"Fast, cheap,", "and private tools win"},
+		},
+		{
+			name:      "phrase words move to the next cue",
+			segmenter: Segmenter{MaxCharsPerLine: 16, MaxLines: 1},
+			segments:  []string{"Install it from the official site"},
+			want:      []string{"Install it", "from the", "official site"},
+		},
+		{
+			name:      "cues never span segments",
+			segmenter: Segmenter{MaxCharsPerLine: 40, MaxLines: 2},
+			segments:  []string{"No full stop here", "next part"},
+			want:      []string{"No full stop here", "next part"},
+		},
+		{
+			name:      "maximum duration splits long runs",
+			segmenter: Segmenter{MaxCharsPerLine: 40, MaxLines: 2, MaxDuration: time.Second},
+			segments:  []string{"one two three four five"},
+			want:      []string{"one two three", "four five"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var segments [][]align.Word
+			start := time.Duration(0)
+			for _, text := range tt.segments {
+				words := timedWords(text, start, step)
+				segments = append(segments, words)
+				start = words[len(words)-1].End
+			}
+
+			got := cueTexts(tt.segmenter.Segment(segments))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cues = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSegmenter_Timing(t *testing.T) {
+	segmenter := Segmenter{
+		MaxCharsPerLine:   40,
+		MaxLines:          2,
+		MinDuration:       800 * time.Millisecond,
+		MaxDuration:       3 * time.Second,
+		MaxCharsPerSecond: 10,
+	}
+
+	// "Go." is short but needs the minimum; "Reading takes time." needs 1.9s
+	// at 10 characters per second, but the next cue starts at 2.5s
+	words := []align.Word{
+		{Text: "Go.", Start: 0, End: 200 * time.Millisecond},
+		{Text: "Reading", Start: time.Second, End: 1200 * time.Millisecond},
+		{Text: "takes", Start: 1200 * time.Millisecond, End: 1400 * time.Millisecond},
+		{Text: "time.", Start: 1400 * time.Millisecond, End: 1600 * time.Millisecond},
+		{Text: "Next.", Start: 2500 * time.Millisecond, End: 2800 * time.Millisecond},
+	}
+
+	cues := segmenter.Segment([][]align.Word{words})
+	if len(cues) != 3 {
+		t.Fatalf("got %d cues, want 3: %q", len(cues), cueTexts(cues))
+	}
+
+	want := [][2]time.Duration{
+		{0, 800 * time.Millisecond},
+		{time.Second, 2500 * time.Millisecond},
+		{2500 * time.Millisecond, 3300 * time.Millisecond},
+	}
+	for i, cue := range cues {
+		if cue.Start != want[i][0] || cue.End != want[i][1] {
+			t.Errorf("cue %d %q = %v-%v, want %v-%v", i, cue.Text(), cue.Start, cue.End, want[i][0], want[i][1])
+		}
+	}
+}
@@ -5,17 +5,21 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"regexp"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/g-laliotis/convertbox/internal/config"
 	"github.com/g-laliotis/convertbox/internal/logger"
+	"github.com/g-laliotis/convertbox/internal/textnorm"
+	"github.com/g-laliotis/convertbox/internal/visuals"
 )
 
 type Service struct {
-	config *config.Config
-	logger *logger.Logger
+	config   *config.Config
+	logger   *logger.Logger
+	assets   *AssetIndex
+	keywords visuals.KeywordMap
 }
 
 type RenderConfig struct {
@@ -24,7 +28,21 @@ type RenderConfig struct {
 	Music       string
 	Logo        string
 	CaptionsSRT string
+	CaptionsASS string // burned instead of CaptionsSRT when set
 	Output      string
+	WorkDir     string        // intermediate files of the multi-pass render
+	Duration    time.Duration // length of the video, the narration's length when zero
+
+	loudness *LoudnessStats // first-pass measurements of the mix
+}
+
+// ScriptSegment is a spoken section of the script with optional visual hints
+type ScriptSegment struct {
+	Text       string
+	Keywords   []string
+	Transition string        // xfade transition into this segment, random when empty
+	Start      time.Duration // narration timing, zero when unknown
+	End        time.Duration
 }
 
 func NewService(cfg *config.Config, log *logger.Logger) *Service {
@@ -38,8 +56,6 @@ func NewService(cfg *config.Config, log *logger.Logger) *Service {
 func (s *Service) CreateBackground(ctx context.Context, outPath string, duration time.Duration) error {
 	s.logger.Info("Creating background (%v duration)", duration)
 
-	sec := int(duration.Seconds())
-	
 	// Check for background images first
 	imageFiles := []string{
 		"assets/images/tech1.jpg",
@@ -51,130 +67,111 @@ func (s *Service) CreateBackground(ctx context.Context, outPath string, duration
 	for _, img := range imageFiles {
 		if _, err := os.Stat(img); err == nil {
 			s.logger.Info("Using background image: %s", img)
-			cmd := exec.CommandContext(ctx, "ffmpeg", "-y",
-				"-loop", "1", "-i", img,
-				"-t", fmt.Sprintf("%d", sec),
-				"-vf", "scale=1080:1920:force_original_aspect_ratio=increase,crop=1080:1920,zoompan=z='min(zoom+0.0015,1.5)':d=125",
-				"-c:v", "libx264", "-preset", "ultrafast", "-pix_fmt", "yuv420p",
-				outPath,
-			)
-			return cmd.Run()
+			return s.runFFmpeg(ctx, s.imageClipArgs(img, outPath, duration, newMotion(s.motionRandom())))
 		}
 	}
 	
 	// Fallback to simple gradient
 	s.logger.Info("No images found, creating simple gradient")
-	cmd := exec.CommandContext(ctx, "ffmpeg", "-y",
-		"-f", "lavfi", "-t", fmt.Sprintf("%d", sec),
-		"-i", "color=c=#0f0f23:s=1080x1920",
-		"-c:v", "libx264", "-preset", "ultrafast", "-pix_fmt", "yuv420p",
-		outPath,
-	)
-	
-	return cmd.Run()
+	return s.runFFmpeg(ctx, s.colorClipArgs(outPath, duration))
 }
 
 func (s *Service) GenerateSubtitles(audioPath, script, outPath string) error {
+	return s.GenerateSegmentSubtitles(audioPath, []ScriptSegment{{Text: script}}, outPath)
+}
+
+// GenerateSegmentSubtitles spreads the words evenly over the narration and
+// writes them as SRT, keeping each caption within one segment
+func (s *Service) GenerateSegmentSubtitles(audioPath string, segments []ScriptSegment, outPath string) error {
 	s.logger.Info("Generating subtitles")
 
-	dur, err := s.getAudioDuration(audioPath)
+	cues, err := s.EvenCaptions(audioPath, segments)
 	if err != nil {
 		return err
 	}
+	return s.WriteCaptions(cues, "srt", outPath)
+}
 
-	// Clean script and split by words for better sync
-	cleanScript := strings.ReplaceAll(script, `"`, "")
-	words := strings.Fields(cleanScript)
-	if len(words) == 0 {
-		return fmt.Errorf("no words found in script")
+// subtitleFilter burns the ASS captions when set, otherwise the SRT
+func (cfg RenderConfig) subtitleFilter() string {
+	if cfg.CaptionsASS != "" {
+		return fmt.Sprintf("ass=%s", cfg.CaptionsASS)
 	}
+	return fmt.Sprintf("subtitles=%s", cfg.CaptionsSRT)
+}
 
-	// Group words for better readability (3-5 words per subtitle)
-	var groups []string
-	wordsPerGroup := 4
-	for i := 0; i < len(words); i += wordsPerGroup {
-		end := i + wordsPerGroup
-		if end > len(words) {
-			end = len(words)
+// RenderVideo renders the final video in a single ffmpeg pass, falling back
+// to separate subtitle, logo and audio passes when that fails or when
+// RENDER_MULTIPASS is set
+func (s *Service) RenderVideo(ctx context.Context, cfg RenderConfig) error {
+	if cfg.Music != "" && cfg.Duration == 0 {
+		if dur, err := s.getAudioDuration(cfg.Narration); err == nil {
+			cfg.Duration = dur
 		}
-		groups = append(groups, strings.Join(words[i:end], " "))
 	}
 
-	segmentDuration := dur / time.Duration(len(groups))
-	var srt strings.Builder
-	currentTime := time.Duration(0)
-
-	for i, group := range groups {
-		nextTime := currentTime + segmentDuration
-		if i == len(groups)-1 {
-			nextTime = dur
+	if s.config.LoudnessTarget != 0 {
+		stats, err := s.measureLoudness(ctx, cfg)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.logger.Warning("Falling back to one-pass loudness normalization: %v", err)
+		} else {
+			s.logger.Info("Measured loudness: %.1f LUFS integrated, %.1f dBTP true peak, %.1f LU range (target %g LUFS, %g dBTP)",
+				stats.InputI, stats.InputTP, stats.InputLRA, s.config.LoudnessTarget, s.config.LoudnessTruePeak)
+			cfg.loudness = stats
 		}
+	}
 
-		fmt.Fprintf(&srt, "%d\n%s --> %s\n%s\n\n",
-			i+1,
-			s.formatSRTTime(currentTime),
-			s.formatSRTTime(nextTime),
-			strings.TrimSpace(group),
-		)
-		currentTime = nextTime
+	if s.config.RenderMultiPass {
+		return s.renderMultiPass(ctx, cfg)
 	}
 
-	return os.WriteFile(outPath, []byte(srt.String()), 0644)
+	s.logger.Info("Rendering final video")
+	if err := s.runFFmpeg(ctx, s.renderArgs(cfg)); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.logger.Warning("Single-pass render failed, retrying in multiple passes: %v", err)
+		return s.renderMultiPass(ctx, cfg)
+	}
+	return nil
 }
 
-func (s *Service) RenderVideo(ctx context.Context, cfg RenderConfig) error {
-	s.logger.Info("Rendering final video")
+func (s *Service) renderMultiPass(ctx context.Context, cfg RenderConfig) error {
+	s.logger.Info("Rendering final video in multiple passes")
 
 	// Step 1: Add subtitles to video
-	tempVideo := "build/temp_with_subs.mp4"
-	cmd1 := exec.CommandContext(ctx, "ffmpeg", "-y",
-		"-i", cfg.VideoInputs[0],
-		"-vf", fmt.Sprintf("subtitles=%s", cfg.CaptionsSRT),
-		"-c:v", "libx264", "-preset", "fast", "-crf", "20",
-		tempVideo,
-	)
-	if err := cmd1.Run(); err != nil {
+	tempVideo := filepath.Join(cfg.WorkDir, "temp_with_subs.mp4")
+	if err := s.runFFmpeg(ctx, s.subtitleArgs(cfg.VideoInputs[0], cfg.subtitleFilter(), tempVideo)); err != nil {
 		return err
 	}
 
 	// Step 2: Add logo if available
 	videoWithLogo := tempVideo
 	if cfg.Logo != "" {
-		videoWithLogo = "build/temp_with_logo.mp4"
-		cmd2 := exec.CommandContext(ctx, "ffmpeg", "-y",
-			"-i", tempVideo,
-			"-i", cfg.Logo,
-			"-filter_complex", "[0:v][1:v]overlay=W-w-20:20",
-			"-c:v", "libx264", "-preset", "fast", "-crf", "20",
-			videoWithLogo,
-		)
-		if err := cmd2.Run(); err != nil {
+		videoWithLogo = filepath.Join(cfg.WorkDir, "temp_with_logo.mp4")
+		if err := s.runFFmpeg(ctx, s.logoArgs(tempVideo, cfg.Logo, videoWithLogo)); err != nil {
 			return err
 		}
 	}
 
 	// Step 3: Add audio
-	args := []string{"-y",
-		"-i", videoWithLogo,
-		"-i", cfg.Narration,
-	}
-	
-	if cfg.Music != "" {
-		args = append(args, "-i", cfg.Music,
-			"-filter_complex", "[1:a]volume=5.0[narr];[2:a]volume=1.0[music];[narr][music]amix=inputs=2:duration=first",
-			"-c:a", "aac", "-b:a", "192k",
-		)
-	} else {
-		args = append(args,
-			"-c:a", "aac", "-b:a", "192k",
-			"-filter:a", "volume=5.0",
-		)
-	}
-	
+	g := &filterGraph{}
+	g.input("-i", videoWithLogo)
+	audio := s.mixAudio(g, cfg, s.renderAudioFilter(cfg))
+
+	args := append([]string{"-y"}, g.args()...)
+	args = append(args, "-map", "0:v", "-map", "["+audio+"]")
+	args = append(args, s.audioEncodeArgs()...)
 	args = append(args, "-c:v", "copy", "-shortest", cfg.Output)
-	
-	cmd3 := exec.CommandContext(ctx, "ffmpeg", args...)
-	return cmd3.Run()
+	return s.runFFmpeg(ctx, args)
+}
+
+// AudioDuration probes the length of an audio file
+func (s *Service) AudioDuration(path string) (time.Duration, error) {
+	return s.getAudioDuration(path)
 }
 
 func (s *Service) getAudioDuration(path string) (time.Duration, error) {
@@ -189,14 +186,12 @@ func (s *Service) getAudioDuration(path string) (time.Duration, error) {
 	return time.Duration(seconds*1000) * time.Millisecond, nil
 }
 
-var sentenceRegex = regexp.MustCompile(`(?m)([^.!?]+[.!?])`)
-
 func (s *Service) splitSentences(text string) []string {
-	matches := sentenceRegex.FindAllString(text, -1)
-	if len(matches) == 0 {
+	sentences := textnorm.SplitSentences(text)
+	if len(sentences) == 0 {
 		return []string{text}
 	}
-	return matches
+	return sentences
 }
 
 func (s *Service) formatSRTTime(d time.Duration) string {
@@ -88,4 +88,4 @@ func TestService_SplitSentences(t *testing.T) {
 			t.Errorf("splitSentences(%q) = %d sentences, want %d", test.input, len(result), test.expected)
 		}
 	}
-}
\ No newline at end of file
+}
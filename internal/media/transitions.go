@@ -0,0 +1,88 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// xfadeTransitions are the ffmpeg xfade transitions that can be configured
+var xfadeTransitions = map[string]bool{
+	"fade": true, "fadeblack": true, "fadewhite": true, "dissolve": true,
+	"slideleft": true, "slideright": true, "slideup": true, "slidedown": true,
+	"wipeleft": true, "wiperight": true, "wipeup": true, "wipedown": true,
+	"smoothleft": true, "smoothright": true, "zoomin": true,
+	"circleopen": true, "circleclose": true, "radial": true, "pixelize": true,
+}
+
+// transitionDuration returns the configured overlap between background
+// segments, shortened so it never takes more than half of any segment
+func (s *Service) transitionDuration(segments []BackgroundSegment) time.Duration {
+	overlap := time.Duration(s.config.TransitionDuration * float64(time.Second))
+	if len(segments) < 2 || overlap <= 0 {
+		return 0
+	}
+	for _, segment := range segments {
+		if half := (segment.EndTime - segment.StartTime) / 2; overlap > half {
+			overlap = half
+		}
+	}
+	return overlap
+}
+
+// assignTransitions fills in the transition into every segment after the
+// first, picking randomly from TRANSITIONS where the script chose none or
+// one xfade does not know
+func (s *Service) assignTransitions(segments []BackgroundSegment, random *rand.Rand) {
+	var choices []string
+	for _, name := range s.config.Transitions {
+		name = strings.ToLower(name)
+		if xfadeTransitions[name] {
+			choices = append(choices, name)
+		} else {
+			s.logger.Warning("Unknown transition %q ignored", name)
+		}
+	}
+	if len(choices) == 0 {
+		choices = []string{"fade"}
+	}
+
+	for i := 1; i < len(segments); i++ {
+		segments[i].Transition = strings.ToLower(segments[i].Transition)
+		if !xfadeTransitions[segments[i].Transition] {
+			segments[i].Transition = choices[random.Intn(len(choices))]
+		}
+	}
+}
+
+// transitionArgs joins the clips with xfade. Every clip but the last runs
+// overlap longer than its segment, so each transition starts where its
+// segment starts and the result is as long as the segments together.
+func (s *Service) transitionArgs(clips []string, segments []BackgroundSegment, overlap time.Duration, outPath string) []string {
+	g := &filterGraph{}
+	for i, clip := range clips {
+		input := g.input("-i", clip)
+		g.chain([]string{stream(input, "v")}, "settb=AVTB,setpts=PTS-STARTPTS", fmt.Sprintf("v%d", i))
+	}
+
+	previous := "v0"
+	for i := 1; i < len(clips); i++ {
+		out := fmt.Sprintf("x%d", i)
+		g.chain([]string{previous, fmt.Sprintf("v%d", i)},
+			fmt.Sprintf("xfade=transition=%s:duration=%.3f:offset=%.3f",
+				segments[i].Transition, overlap.Seconds(), (segments[i].StartTime-segments[0].StartTime).Seconds()),
+			out)
+		previous = out
+	}
+
+	args := append([]string{"-y"}, g.args()...)
+	args = append(args, "-map", "["+previous+"]")
+	args = append(args, s.encodeArgs()...)
+	return append(args, outPath)
+}
+
+func (s *Service) joinWithTransitions(ctx context.Context, clips []string, segments []BackgroundSegment, overlap time.Duration, outPath string) error {
+	return s.runFFmpeg(ctx, s.transitionArgs(clips, segments, overlap, outPath))
+}
@@ -0,0 +1,76 @@
+package media
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+	"github.com/g-laliotis/convertbox/internal/logger"
+)
+
+func testSegments(bounds ...time.Duration) []BackgroundSegment {
+	var segments []BackgroundSegment
+	for i := 1; i < len(bounds); i++ {
+		segments = append(segments, BackgroundSegment{StartTime: bounds[i-1], EndTime: bounds[i]})
+	}
+	return segments
+}
+
+func TestService_TransitionDuration(t *testing.T) {
+	service := NewService(&config.Config{TransitionDuration: 0.5}, logger.New())
+
+	if got := service.transitionDuration(testSegments(0, 3*time.Second, 7*time.Second)); got != 500*time.Millisecond {
+		t.Errorf("overlap = %v, want 500ms", got)
+	}
+	// A 600ms segment limits the overlap to 300ms
+	if got := service.transitionDuration(testSegments(0, 3*time.Second, 3600*time.Millisecond, 7*time.Second)); got != 300*time.Millisecond {
+		t.Errorf("overlap = %v, want 300ms", got)
+	}
+	if got := service.transitionDuration(testSegments(0, 3*time.Second)); got != 0 {
+		t.Errorf("single segment overlap = %v, want 0", got)
+	}
+}
+
+func TestService_AssignTransitions(t *testing.T) {
+	service := NewService(&config.Config{Transitions: []string{"slideleft", "CircleOpen", "sparkles"}}, logger.New())
+
+	segments := testSegments(0, time.Second, 2*time.Second, 3*time.Second, 4*time.Second)
+	segments[2].Transition = "radial"
+	service.assignTransitions(segments, rand.New(rand.NewSource(1)))
+
+	if segments[0].Transition != "" {
+		t.Errorf("first segment has no transition into it, got %q", segments[0].Transition)
+	}
+	if segments[2].Transition != "radial" {
+		t.Errorf("chosen transition replaced with %q", segments[2].Transition)
+	}
+	for _, i := range []int{1, 3} {
+		if tr := segments[i].Transition; tr != "slideleft" && tr != "circleopen" {
+			t.Errorf("segment %d transition %q not from the configured set", i, tr)
+		}
+	}
+}
+
+func TestService_TransitionArgs(t *testing.T) {
+	service := NewService(&config.Config{}, logger.New())
+
+	segments := testSegments(0, 3*time.Second, 7*time.Second, 10*time.Second)
+	segments[1].Transition = "fade"
+	segments[2].Transition = "wipeleft"
+
+	joined := strings.Join(service.transitionArgs([]string{"a.mp4", "b.mp4", "c.mp4"}, segments, 500*time.Millisecond, "out.mp4"), " ")
+
+	// Transitions start where their segment starts, keeping the total at 10s
+	for _, want := range []string{
+		"-i a.mp4 -i b.mp4 -i c.mp4",
+		"[v0][v1]xfade=transition=fade:duration=0.500:offset=3.000[x1]",
+		"[x1][v2]xfade=transition=wipeleft:duration=0.500:offset=7.000[x2]",
+		"-map [x2]",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("args missing %q: %s", want, joined)
+		}
+	}
+}
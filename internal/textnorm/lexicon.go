@@ -0,0 +1,46 @@
+package textnorm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LoadLexicon reads a lexicon file of "written = spoken" lines
+func LoadLexicon(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseLexicon(f)
+}
+
+// ParseLexicon parses "written = spoken" lines, skipping blanks and # comments
+func ParseLexicon(r io.Reader) (map[string]string, error) {
+	lexicon := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		written, spoken, ok := strings.Cut(text, "=")
+		written, spoken = strings.TrimSpace(written), strings.TrimSpace(spoken)
+		if !ok || written == "" || spoken == "" {
+			return nil, fmt.Errorf("lexicon line %d: want \"written = spoken\", got %q", line, text)
+		}
+		lexicon[written] = spoken
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lexicon, nil
+}
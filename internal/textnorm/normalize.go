@@ -0,0 +1,230 @@
+// Package textnorm rewrites script text into a form TTS engines pronounce
+// correctly: lexicon respellings, spelled-out numbers, dates, currency and URLs.
+package textnorm
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Normalizer applies the lexicon and the built-in expansion rules
+type Normalizer struct {
+	lexicon   map[string]string
+	lexiconRe *regexp.Regexp
+}
+
+var (
+	urlRegex      = regexp.MustCompile(`(?i)\b(?:https?://)?(?:www\.)?((?:[a-z0-9-]+\.)+(?:com|org|net|io|ai|dev|app|co|gg|me|so|tv|xyz|tech))\b(/[^\s]*)?`)
+	isoDateRegex  = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	currencyRegex = regexp.MustCompile(`([$€£])(\d{1,3}(?:,\d{3})+|\d+)(?:\.(\d{1,2}))?(?:\s?(k|K|M|B|thousand|million|billion|trillion)\b)?`)
+	percentRegex  = regexp.MustCompile(`(\d+(?:\.\d+)?)\s?%`)
+	versionRegex  = regexp.MustCompile(`\b[vV](\d+(?:\.\d+)*)\b`)
+	ordinalRegex  = regexp.MustCompile(`\b(\d+)(?:st|nd|rd|th)\b`)
+	suffixRegex   = regexp.MustCompile(`\b(\d+(?:\.\d+)?)([a-zA-Z]+)\b`)
+	numberRegex   = regexp.MustCompile(`\b\d{1,3}(?:,\d{3})+(?:\.\d+)?\b|\b\d+(?:\.\d+)?\b`)
+	spacesRegex   = regexp.MustCompile(`[ \t]+`)
+)
+
+var currencyNames = map[string][2]string{
+	"$": {"dollar", "dollars"},
+	"€": {"euro", "euros"},
+	"£": {"pound", "pounds"},
+}
+
+var scaleSuffixes = map[string]string{
+	"k": "thousand", "K": "thousand", "M": "million", "B": "billion",
+}
+
+var tldNames = map[string]string{
+	"ai": "A.I.", "io": "I O", "co": "C O", "tv": "T V", "gg": "G G",
+}
+
+// New builds a normalizer from a lexicon of written -> spoken entries
+func New(lexicon map[string]string) *Normalizer {
+	n := &Normalizer{lexicon: lexicon}
+	if len(lexicon) == 0 {
+		return n
+	}
+
+	// Longest entries first so "GPT-4o" wins over "GPT"
+	terms := make([]string, 0, len(lexicon))
+	for term := range lexicon {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if len(terms[i]) != len(terms[j]) {
+			return len(terms[i]) > len(terms[j])
+		}
+		return terms[i] < terms[j]
+	})
+
+	patterns := make([]string, len(terms))
+	for i, term := range terms {
+		patterns[i] = wordBoundary(term, true) + regexp.QuoteMeta(term) + wordBoundary(term, false)
+	}
+	n.lexiconRe = regexp.MustCompile(strings.Join(patterns, "|"))
+	return n
+}
+
+// Load builds a normalizer from a lexicon file
+func Load(path string) (*Normalizer, error) {
+	lexicon, err := LoadLexicon(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(lexicon), nil
+}
+
+// Normalize returns text rewritten for speech
+func (n *Normalizer) Normalize(text string) string {
+	text = urlRegex.ReplaceAllStringFunc(text, speakURL)
+	if n.lexiconRe != nil {
+		text = n.lexiconRe.ReplaceAllStringFunc(text, func(term string) string {
+			return n.lexicon[term]
+		})
+	}
+	text = isoDateRegex.ReplaceAllStringFunc(text, speakDate)
+	text = currencyRegex.ReplaceAllStringFunc(text, speakCurrency)
+	text = percentRegex.ReplaceAllStringFunc(text, func(m string) string {
+		return Decimal(percentRegex.FindStringSubmatch(m)[1]) + " percent"
+	})
+	text = versionRegex.ReplaceAllStringFunc(text, func(m string) string {
+		parts := strings.Split(m[1:], ".")
+		for i, part := range parts {
+			parts[i] = Decimal(part)
+		}
+		return "version " + strings.Join(parts, " point ")
+	})
+	text = ordinalRegex.ReplaceAllStringFunc(text, func(m string) string {
+		n, err := strconv.ParseInt(ordinalRegex.FindStringSubmatch(m)[1], 10, 64)
+		if err != nil {
+			return m
+		}
+		return Ordinal(n)
+	})
+	text = suffixRegex.ReplaceAllStringFunc(text, speakNumberSuffix)
+	text = numberRegex.ReplaceAllStringFunc(text, speakNumber)
+	return strings.TrimSpace(spacesRegex.ReplaceAllString(text, " "))
+}
+
+// wordBoundary returns \b only when the term edge is a word character,
+// otherwise \b would never match terms like ".NET"
+func wordBoundary(term string, start bool) string {
+	edge := term[len(term)-1]
+	if start {
+		edge = term[0]
+	}
+	if edge == '_' || edge >= '0' && edge <= '9' || edge >= 'a' && edge <= 'z' || edge >= 'A' && edge <= 'Z' {
+		return `\b`
+	}
+	return ""
+}
+
+func speakURL(m string) string {
+	match := urlRegex.FindStringSubmatch(m)
+	labels := strings.Split(strings.ToLower(match[1]), ".")
+	if spoken, ok := tldNames[labels[len(labels)-1]]; ok {
+		labels[len(labels)-1] = spoken
+	}
+	spoken := strings.Join(labels, " dot ")
+
+	// Keep trailing sentence punctuation out of the path
+	path := match[2]
+	trailing := ""
+	for len(path) > 0 && strings.ContainsRune(".,!?;:)", rune(path[len(path)-1])) {
+		trailing = path[len(path)-1:] + trailing
+		path = path[:len(path)-1]
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		segment = strings.NewReplacer("-", " dash ", "_", " underscore ", ".", " dot ").Replace(segment)
+		spoken += " slash " + segment
+	}
+	return spoken + trailing
+}
+
+func speakDate(m string) string {
+	date, err := time.Parse("2006-01-02", m)
+	if err != nil {
+		return m
+	}
+	return date.Month().String() + " " + Ordinal(int64(date.Day())) + ", " + Year(date.Year())
+}
+
+func speakCurrency(m string) string {
+	match := currencyRegex.FindStringSubmatch(m)
+	names := currencyNames[match[1]]
+	amount := strings.ReplaceAll(match[2], ",", "")
+	cents, scale := match[3], match[4]
+
+	if scale != "" {
+		if word, ok := scaleSuffixes[scale]; ok {
+			scale = word
+		}
+		number := amount
+		if cents != "" {
+			number += "." + cents
+		}
+		return Decimal(number) + " " + scale + " " + names[1]
+	}
+
+	whole, err := strconv.ParseInt(amount, 10, 64)
+	if err != nil {
+		return m
+	}
+	unit := names[1]
+	if whole == 1 {
+		unit = names[0]
+	}
+	spoken := Cardinal(whole) + " " + unit
+
+	if cents != "" {
+		if len(cents) == 1 {
+			cents += "0"
+		}
+		c, _ := strconv.Atoi(cents)
+		if c == 1 {
+			spoken += " and one cent"
+		} else if c > 0 {
+			spoken += " and " + Cardinal(int64(c)) + " cents"
+		}
+	}
+	return spoken
+}
+
+func speakNumberSuffix(m string) string {
+	match := suffixRegex.FindStringSubmatch(m)
+	number, suffix := match[1], match[2]
+
+	switch {
+	case suffix == "s" && strings.Contains(number, "."):
+		return Decimal(number) + " seconds" // 0.5s
+	case suffix == "s":
+		n, _ := strconv.Atoi(number)
+		if len(number) == 4 {
+			return Plural(Year(n)) // 1990s -> nineteen nineties
+		}
+		return Plural(Cardinal(int64(n)))
+	case suffix == "x":
+		return Decimal(number) + " times"
+	case scaleSuffixes[suffix] != "" && suffix != "K": // 4K is a resolution, not 4000
+		return Decimal(number) + " " + scaleSuffixes[suffix]
+	default:
+		return Decimal(number) + " " + suffix
+	}
+}
+
+func speakNumber(m string) string {
+	if len(m) == 4 && !strings.ContainsAny(m, ".,") {
+		if n, err := strconv.Atoi(m); err == nil && n >= 1100 && n < 2100 {
+			return Year(n)
+		}
+	}
+	return Decimal(m)
+}
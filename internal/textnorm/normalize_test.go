@@ -0,0 +1,119 @@
+package textnorm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizer_Normalize(t *testing.T) {
+	n := New(map[string]string{
+		"AI":     "A.I.",
+		"GPT":    "G P T",
+		"GPT-4o": "G P T four oh",
+		".NET":   "dot net",
+	})
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"AI is here.", "A.I. is here."},
+		{"Thai food", "Thai food"},
+		{"GPT-4o beats GPT.", "G P T four oh beats G P T."},
+		{"Built on .NET today", "Built on dot net today"},
+		{"In 2025, 3 tools", "In twenty twenty-five, three tools"},
+		{"It costs $20 a month", "It costs twenty dollars a month"},
+		{"Only $1.99!", "Only one dollar and ninety-nine cents!"},
+		{"Raised $1.5M in funding", "Raised one point five million dollars in funding"},
+		{"Save 50% now", "Save fifty percent now"},
+		{"Update to v1.5 today", "Update to version one point five today"},
+		{"The 1st and 22nd", "The first and twenty-second"},
+		{"Launched 2024-03-01", "Launched March first, twenty twenty-four"},
+		{"1,000,000 users", "one million users"},
+		{"Pi is 3.14", "Pi is three point one four"},
+		{"10x faster in 4K", "ten times faster in four K"},
+		{"1.5M users", "one point five million users"},
+		{"3.5x faster", "three point five times faster"},
+		{"A 2.5GHz chip", "A two point five GHz chip"},
+		{"Replies in 0.5s", "Replies in zero point five seconds"},
+		{"Over 10k users", "Over ten thousand users"},
+		{"Back in the 1990s", "Back in the nineteen nineties"},
+		{"Visit https://www.perplexity.ai/discover.", "Visit perplexity dot A.I. slash discover."},
+		{"Try chat.openai.com now", "Try chat dot openai dot com now"},
+	}
+
+	for _, test := range tests {
+		if got := n.Normalize(test.input); got != test.want {
+			t.Errorf("Normalize(%q) = %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestParseLexicon(t *testing.T) {
+	lexicon, err := ParseLexicon(strings.NewReader("# comment\n\nAI = A.I.\n  SaaS=sass  \n"))
+	if err != nil {
+		t.Fatalf("ParseLexicon failed: %v", err)
+	}
+	if len(lexicon) != 2 || lexicon["AI"] != "A.I." || lexicon["SaaS"] != "sass" {
+		t.Errorf("lexicon = %v", lexicon)
+	}
+
+	if _, err := ParseLexicon(strings.NewReader("AI A.I.")); err == nil {
+		t.Error("expected error for line without '='")
+	}
+}
+
+func TestLoad_BundledLexicon(t *testing.T) {
+	n, err := Load("../../assets/lexicon.txt")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := n.Normalize("ChatGPT and GPT-4o"); got != "Chat G.P.T. and G.P.T. four oh" {
+		t.Errorf("Normalize = %q", got)
+	}
+	if got := n.Normalize("U.S. and A.I."); got != "U.S. and A.I." {
+		t.Errorf("Normalize = %q", got)
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"Hello world.", []string{"Hello world."}},
+		{"One. Two! Three?", []string{"One.", "Two!", "Three?"}},
+		{"No punctuation", []string{"No punctuation"}},
+		{"A.I. is here. Version 1.5 ships today", []string{"A.I. is here.", "Version 1.5 ships today"}},
+		{`Wait... what?! "Really." Yes`, []string{"Wait...", "what?!", `"Really."`, "Yes"}},
+		{"Visit openai.com today.", []string{"Visit openai.com today."}},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		got := SplitSentences(test.input)
+		if strings.Join(got, "|") != strings.Join(test.want, "|") {
+			t.Errorf("SplitSentences(%q) = %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestEndsSentence(t *testing.T) {
+	tests := map[string]bool{
+		"done.":     true,
+		"what?!":    true,
+		`"Really."`: true,
+		"Wait...":   true,
+		"A.I.":      false,
+		"U.S.":      false,
+		"hello":     false,
+		"1.5":       false,
+		"first,":    false,
+	}
+
+	for word, want := range tests {
+		if got := EndsSentence(word); got != want {
+			t.Errorf("EndsSentence(%q) = %v, want %v", word, got, want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package textnorm
+
+import (
+	"strconv"
+	"strings"
+)
+
+var (
+	ones = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	tens   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	scales = []struct {
+		value int64
+		name  string
+	}{
+		{1_000_000_000_000, "trillion"},
+		{1_000_000_000, "billion"},
+		{1_000_000, "million"},
+		{1_000, "thousand"},
+	}
+	irregularOrdinals = map[string]string{
+		"one": "first", "two": "second", "three": "third", "five": "fifth",
+		"eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+	}
+)
+
+// Cardinal spells out a non-negative integer, e.g. 1205 -> "one thousand two hundred five"
+func Cardinal(n int64) string {
+	if n < 0 {
+		return "minus " + Cardinal(-n)
+	}
+	if n < 100 {
+		return underHundred(int(n))
+	}
+
+	var parts []string
+	for _, scale := range scales {
+		if n >= scale.value {
+			parts = append(parts, Cardinal(n/scale.value), scale.name)
+			n %= scale.value
+		}
+	}
+	if n >= 100 {
+		parts = append(parts, ones[n/100], "hundred")
+		n %= 100
+	}
+	if n > 0 {
+		parts = append(parts, underHundred(int(n)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Ordinal spells out n as an ordinal, e.g. 21 -> "twenty-first"
+func Ordinal(n int64) string {
+	words := Cardinal(n)
+	cut := strings.LastIndexAny(words, " -") + 1
+	last := words[cut:]
+
+	switch {
+	case irregularOrdinals[last] != "":
+		last = irregularOrdinals[last]
+	case strings.HasSuffix(last, "y"):
+		last = strings.TrimSuffix(last, "y") + "ieth"
+	default:
+		last += "th"
+	}
+	return words[:cut] + last
+}
+
+// Year reads a four digit year the way people say it, e.g. 2025 -> "twenty twenty-five"
+func Year(n int) string {
+	if n < 1000 || n > 9999 {
+		return Cardinal(int64(n))
+	}
+	if n%1000 < 10 && n/1000 == 2 {
+		return Cardinal(int64(n)) // 2000-2009 read as "two thousand five"
+	}
+
+	high, low := n/100, n%100
+	switch {
+	case low == 0:
+		return underHundred(high) + " hundred"
+	case low < 10:
+		return underHundred(high) + " oh " + ones[low]
+	default:
+		return underHundred(high) + " " + underHundred(low)
+	}
+}
+
+// Decimal reads a decimal string digit by digit after the point, e.g. "1.05" -> "one point zero five"
+func Decimal(s string) string {
+	s = strings.ReplaceAll(s, ",", "")
+	whole, frac, hasFrac := strings.Cut(s, ".")
+
+	n, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return s
+	}
+	words := Cardinal(n)
+	if !hasFrac || frac == "" {
+		return words
+	}
+
+	digits := make([]string, 0, len(frac))
+	for _, d := range frac {
+		digits = append(digits, ones[d-'0'])
+	}
+	return words + " point " + strings.Join(digits, " ")
+}
+
+// Plural turns a spelled number into its plural, e.g. "ninety" -> "nineties"
+func Plural(words string) string {
+	switch {
+	case strings.HasSuffix(words, "y"):
+		return strings.TrimSuffix(words, "y") + "ies"
+	case strings.HasSuffix(words, "x"):
+		return words + "es"
+	default:
+		return words + "s"
+	}
+}
+
+func underHundred(n int) string {
+	if n < 20 {
+		return ones[n]
+	}
+	if n%10 == 0 {
+		return tens[n/10]
+	}
+	return tens[n/10] + "-" + ones[n%10]
+}
@@ -0,0 +1,55 @@
+package textnorm
+
+import "testing"
+
+func TestCardinal(t *testing.T) {
+	tests := map[int64]string{
+		0:         "zero",
+		13:        "thirteen",
+		40:        "forty",
+		99:        "ninety-nine",
+		100:       "one hundred",
+		1205:      "one thousand two hundred five",
+		2000000:   "two million",
+		123456789: "one hundred twenty-three million four hundred fifty-six thousand seven hundred eighty-nine",
+	}
+	for n, want := range tests {
+		if got := Cardinal(n); got != want {
+			t.Errorf("Cardinal(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	tests := map[int64]string{
+		1:   "first",
+		2:   "second",
+		3:   "third",
+		12:  "twelfth",
+		20:  "twentieth",
+		21:  "twenty-first",
+		100: "one hundredth",
+	}
+	for n, want := range tests {
+		if got := Ordinal(n); got != want {
+			t.Errorf("Ordinal(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestYear(t *testing.T) {
+	tests := map[int]string{
+		1900: "nineteen hundred",
+		1905: "nineteen oh five",
+		1999: "nineteen ninety-nine",
+		2000: "two thousand",
+		2007: "two thousand seven",
+		2010: "twenty ten",
+		2025: "twenty twenty-five",
+	}
+	for n, want := range tests {
+		if got := Year(n); got != want {
+			t.Errorf("Year(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
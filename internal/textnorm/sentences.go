@@ -0,0 +1,83 @@
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SplitSentences splits text at sentence-ending punctuation followed by
+// whitespace. Initialisms such as "A.I." and decimals such as "1.5" do not end
+// a sentence, and trailing text without punctuation is kept.
+func SplitSentences(text string) []string {
+	var sentences []string
+	runes := []rune(text)
+	start := 0
+
+	for i := 0; i < len(runes); i++ {
+		if !strings.ContainsRune(".!?", runes[i]) {
+			continue
+		}
+
+		// Swallow runs like "?!" or "..." and closing quotes/brackets
+		end := i + 1
+		for end < len(runes) && strings.ContainsRune(".!?\"')]”’", runes[end]) {
+			end++
+		}
+		if end < len(runes) && !unicode.IsSpace(runes[end]) {
+			i = end - 1
+			continue
+		}
+		if runes[i] == '.' && isInitialism(lastWord(runes[start:end])) {
+			i = end - 1
+			continue
+		}
+
+		if sentence := strings.TrimSpace(string(runes[start:end])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = end
+		i = end - 1
+	}
+
+	if rest := strings.TrimSpace(string(runes[start:])); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	return sentences
+}
+
+func lastWord(runes []rune) string {
+	fields := strings.Fields(string(runes))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+// isInitialism reports words like "A.I." or "U.S." made of single letters and dots
+func isInitialism(word string) bool {
+	word = strings.TrimRight(word, "\"')]”’")
+	letters := 0
+	for i, r := range []rune(word) {
+		if i%2 == 1 {
+			if r != '.' {
+				return false
+			}
+			continue
+		}
+		if !unicode.IsLetter(r) {
+			return false
+		}
+		letters++
+	}
+	return letters >= 2 && strings.HasSuffix(word, ".")
+}
+
+// EndsSentence reports whether a single word closes a sentence, using the
+// same rules as SplitSentences
+func EndsSentence(word string) bool {
+	trimmed := strings.TrimRight(word, "\"')]”’")
+	if trimmed == "" || !strings.ContainsRune(".!?", []rune(trimmed)[len([]rune(trimmed))-1]) {
+		return false
+	}
+	return !isInitialism(trimmed)
+}
@@ -0,0 +1,210 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+)
+
+// CoquiEngine runs the Coqui TTS command line tool
+type CoquiEngine struct {
+	config *config.Config
+}
+
+func NewCoquiEngine(cfg *config.Config) *CoquiEngine {
+	return &CoquiEngine{config: cfg}
+}
+
+func (e *CoquiEngine) Name() string {
+	return "coqui"
+}
+
+func (e *CoquiEngine) Speak(ctx context.Context, text, outPath string) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "tts", e.args(text, outPath)...)
+	var errOut bytes.Buffer
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, lastLine(errOut.String()))
+	}
+	return nil
+}
+
+// coquiBatchScript loads the model once through the TTS Python API and
+// writes one WAV per job read from stdin
+const coquiBatchScript = `import json, sys
+from TTS.api import TTS
+req = json.load(sys.stdin)
+tts = TTS(req["model"])
+kwargs = {}
+if req.get("speaker_wav"):
+    kwargs["speaker_wav"] = req["speaker_wav"]
+elif req.get("speaker") and tts.is_multi_speaker:
+    kwargs["speaker"] = req["speaker"]
+if req.get("language") and tts.is_multi_lingual:
+    kwargs["language"] = req["language"]
+for job in req["jobs"]:
+    tts.tts_to_file(text=job["text"], file_path=job["out"], **kwargs)
+`
+
+type coquiBatchJob struct {
+	Text string `json:"text"`
+	Out  string `json:"out"`
+}
+
+type coquiBatchRequest struct {
+	Model      string          `json:"model"`
+	Speaker    string          `json:"speaker,omitempty"`
+	Language   string          `json:"language,omitempty"`
+	SpeakerWav string          `json:"speaker_wav,omitempty"`
+	Jobs       []coquiBatchJob `json:"jobs"`
+}
+
+// SpeakBatch synthesizes every text in one Python process so the model is
+// loaded once per narration instead of once per sentence. With COQUI_BATCH
+// off it falls back to one tts CLI run per text.
+func (e *CoquiEngine) SpeakBatch(ctx context.Context, texts, outPaths []string) error {
+	if !e.config.CoquiBatch {
+		for i, text := range texts {
+			if err := e.Speak(ctx, text, outPaths[i]); err != nil {
+				return fmt.Errorf("sentence %d: %w", i+1, err)
+			}
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Minute+time.Duration(len(texts))*30*time.Second)
+	defer cancel()
+
+	input, err := json.Marshal(e.batchRequest(texts, outPaths))
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, e.config.CoquiPython, "-c", coquiBatchScript)
+	cmd.Stdin = bytes.NewReader(input)
+	var errOut bytes.Buffer
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, lastLine(errOut.String()))
+	}
+	return nil
+}
+
+func (e *CoquiEngine) batchRequest(texts, outPaths []string) coquiBatchRequest {
+	req := coquiBatchRequest{
+		Model:      e.config.CoquiModel,
+		Language:   e.config.CoquiLanguage,
+		SpeakerWav: e.config.CoquiSpeakerWav,
+	}
+	if req.SpeakerWav == "" {
+		req.Speaker = e.config.CoquiSpeaker
+	}
+	for i, text := range texts {
+		req.Jobs = append(req.Jobs, coquiBatchJob{Text: text, Out: outPaths[i]})
+	}
+	return req
+}
+
+func (e *CoquiEngine) args(text, outPath string) []string {
+	args := []string{
+		"--text", text,
+		"--model_name", e.config.CoquiModel,
+		"--out_path", outPath,
+	}
+
+	// A reference recording (XTTS voice cloning) replaces the named speaker
+	if e.config.CoquiSpeakerWav != "" {
+		args = append(args, "--speaker_wav", e.config.CoquiSpeakerWav)
+	} else if e.config.CoquiSpeaker != "" {
+		args = append(args, "--speaker_idx", e.config.CoquiSpeaker)
+	}
+	if e.config.CoquiLanguage != "" {
+		args = append(args, "--language_idx", e.config.CoquiLanguage)
+	}
+	return args
+}
+
+// ListModels returns the TTS models Coqui knows about
+func (e *CoquiEngine) ListModels(ctx context.Context) ([]string, error) {
+	out, err := e.run(ctx, "--list_models")
+	if err != nil {
+		return nil, err
+	}
+	return parseModelList(out), nil
+}
+
+// ListSpeakers returns the speaker IDs of a multi-speaker model
+func (e *CoquiEngine) ListSpeakers(ctx context.Context, model string) ([]string, error) {
+	out, err := e.run(ctx, "--model_name", model, "--list_speaker_idxs")
+	if err != nil {
+		return nil, err
+	}
+	return parseIndexList(out), nil
+}
+
+// ListLanguages returns the language IDs of a multilingual model
+func (e *CoquiEngine) ListLanguages(ctx context.Context, model string) ([]string, error) {
+	out, err := e.run(ctx, "--model_name", model, "--list_language_idxs")
+	if err != nil {
+		return nil, err
+	}
+	return parseIndexList(out), nil
+}
+
+func (e *CoquiEngine) run(ctx context.Context, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	var out, errOut bytes.Buffer
+	cmd := exec.CommandContext(ctx, "tts", args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tts %s failed: %w: %s", strings.Join(args, " "), err, lastLine(errOut.String()))
+	}
+	return out.String(), nil
+}
+
+var (
+	modelNameRegex = regexp.MustCompile(`\btts_models/[^\s\]]+`)
+	indexKeyRegex  = regexp.MustCompile(`'([^']+)'\s*:`)
+)
+
+// parseModelList extracts model names from `tts --list_models` output,
+// e.g. " 12: tts_models/en/vctk/vits [already downloaded]"
+func parseModelList(out string) []string {
+	var models []string
+	seen := make(map[string]bool)
+	for _, model := range modelNameRegex.FindAllString(out, -1) {
+		if !seen[model] {
+			seen[model] = true
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// parseIndexList extracts the keys of the Python dict printed by
+// --list_speaker_idxs and --list_language_idxs
+func parseIndexList(out string) []string {
+	var keys []string
+	for _, match := range indexKeyRegex.FindAllStringSubmatch(out, -1) {
+		keys = append(keys, match[1])
+	}
+	return keys
+}
+
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
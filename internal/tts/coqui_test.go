@@ -0,0 +1,69 @@
+package tts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+)
+
+func TestParseModelList(t *testing.T) {
+	out := ` Name format: type/language/dataset/model
+ 1: tts_models/multilingual/multi-dataset/xtts_v2 [already downloaded]
+ 2: tts_models/en/ljspeech/tacotron2-DDC
+ 3: tts_models/en/vctk/vits [already downloaded]
+ Name format: type/language/dataset/model
+ 1: vocoder_models/universal/libri-tts/wavegrad
+`
+	models := parseModelList(out)
+	want := []string{
+		"tts_models/multilingual/multi-dataset/xtts_v2",
+		"tts_models/en/ljspeech/tacotron2-DDC",
+		"tts_models/en/vctk/vits",
+	}
+	if strings.Join(models, ",") != strings.Join(want, ",") {
+		t.Errorf("parseModelList = %v, want %v", models, want)
+	}
+}
+
+func TestParseIndexList(t *testing.T) {
+	out := ` > Available speaker ids: (Set --speaker_idx flag to one of these values to use the multi-speaker model.
+{'ED\n': 0, 'p225': 1, 'p226': 2}`
+	speakers := parseIndexList(out)
+	if len(speakers) != 3 || speakers[1] != "p225" {
+		t.Errorf("parseIndexList = %q", speakers)
+	}
+}
+
+func TestCoquiEngine_Args(t *testing.T) {
+	cfg := &config.Config{CoquiModel: "tts_models/en/vctk/vits", CoquiSpeaker: "p230"}
+	engine := NewCoquiEngine(cfg)
+
+	args := strings.Join(engine.args("hi", "out.wav"), " ")
+	if !strings.Contains(args, "--model_name tts_models/en/vctk/vits") || !strings.Contains(args, "--speaker_idx p230") {
+		t.Errorf("args = %s", args)
+	}
+
+	cfg.CoquiModel = "tts_models/multilingual/multi-dataset/xtts_v2"
+	cfg.CoquiSpeakerWav = "voice.wav"
+	cfg.CoquiLanguage = "en"
+	args = strings.Join(engine.args("hi", "out.wav"), " ")
+	if strings.Contains(args, "--speaker_idx") || !strings.Contains(args, "--speaker_wav voice.wav") || !strings.Contains(args, "--language_idx en") {
+		t.Errorf("cloning args = %s", args)
+	}
+}
+
+func TestCoquiEngine_BatchRequest(t *testing.T) {
+	cfg := &config.Config{CoquiModel: "tts_models/en/vctk/vits", CoquiSpeaker: "p230"}
+	engine := NewCoquiEngine(cfg)
+
+	req := engine.batchRequest([]string{"Hello.", "Bye."}, []string{"a.wav", "b.wav"})
+	if req.Model != cfg.CoquiModel || req.Speaker != "p230" || len(req.Jobs) != 2 || req.Jobs[1].Out != "b.wav" {
+		t.Errorf("batch request = %+v", req)
+	}
+
+	cfg.CoquiSpeakerWav = "voice.wav"
+	if req := engine.batchRequest([]string{"Hi."}, []string{"a.wav"}); req.Speaker != "" || req.SpeakerWav != "voice.wav" {
+		t.Errorf("cloning batch request = %+v", req)
+	}
+}
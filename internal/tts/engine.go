@@ -0,0 +1,65 @@
+package tts
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+)
+
+// Engine synthesizes text into a WAV file
+type Engine interface {
+	Name() string
+	Speak(ctx context.Context, text, outPath string) error
+}
+
+// BatchEngine is an engine that synthesizes several texts in one run, for
+// engines whose start-up (loading a model) costs more than the speech itself
+type BatchEngine interface {
+	Engine
+	SpeakBatch(ctx context.Context, texts, outPaths []string) error
+}
+
+// EngineFactory builds an engine from the configuration
+type EngineFactory func(cfg *config.Config) Engine
+
+var registry = map[string]EngineFactory{
+	"coqui":  func(cfg *config.Config) Engine { return NewCoquiEngine(cfg) },
+	"espeak": func(cfg *config.Config) Engine { return NewESpeakEngine(cfg) },
+	"piper":  func(cfg *config.Config) Engine { return NewPiperEngine(cfg) },
+}
+
+// Register makes an engine available to TTS_ENGINES under name
+func Register(name string, factory EngineFactory) {
+	registry[strings.ToLower(name)] = factory
+}
+
+// Engines returns the registered engine names
+func Engines() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// engineOrder returns TTS_ENGINES, or TTS_ENGINE followed by espeak when unset
+func engineOrder(cfg *config.Config) []string {
+	order := cfg.TTSEngines
+	if len(order) == 0 {
+		order = []string{cfg.TTSEngine, "espeak"}
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range order {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,36 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+)
+
+// ESpeakEngine is the always-available robotic fallback
+type ESpeakEngine struct {
+	config *config.Config
+}
+
+func NewESpeakEngine(cfg *config.Config) *ESpeakEngine {
+	return &ESpeakEngine{config: cfg}
+}
+
+func (e *ESpeakEngine) Name() string {
+	return "espeak"
+}
+
+func (e *ESpeakEngine) Speak(ctx context.Context, text, outPath string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "espeak-ng",
+		"-v", e.config.ESpeakVoice,
+		"-s", fmt.Sprintf("%d", e.config.ESpeakSpeed),
+		"-w", outPath,
+		text,
+	)
+	return cmd.Run()
+}
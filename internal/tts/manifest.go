@@ -0,0 +1,57 @@
+package tts
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SentenceTiming locates one sentence inside the narration WAV
+type SentenceTiming struct {
+	Index  int     `json:"index"`
+	Text   string  `json:"text"`
+	Spoken string  `json:"spoken"`
+	Start  float64 `json:"start"`
+	End    float64 `json:"end"`
+}
+
+// Manifest describes how the narration was assembled
+type Manifest struct {
+	Engine    string           `json:"engine"`
+	Audio     string           `json:"audio"`
+	Duration  float64          `json:"duration"`
+	Sentences []SentenceTiming `json:"sentences"`
+}
+
+// ManifestPath returns where the manifest for a narration file is written,
+// e.g. build/narration.wav -> build/narration.json
+func ManifestPath(audioPath string) string {
+	return strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + ".json"
+}
+
+// LoadManifest reads a manifest written by Synthesize
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+func (m *Manifest) save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func seconds(d time.Duration) float64 {
+	return float64(d.Milliseconds()) / 1000
+}
@@ -0,0 +1,68 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+)
+
+// PiperEngine runs Piper, a fast CPU-only neural TTS
+type PiperEngine struct {
+	config *config.Config
+}
+
+func NewPiperEngine(cfg *config.Config) *PiperEngine {
+	return &PiperEngine{config: cfg}
+}
+
+func (e *PiperEngine) Name() string {
+	return "piper"
+}
+
+func (e *PiperEngine) Speak(ctx context.Context, text, outPath string) error {
+	if _, err := os.Stat(e.config.PiperModel); err != nil {
+		return fmt.Errorf("piper model not available: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	// Piper reads the text from stdin
+	cmd := exec.CommandContext(ctx, e.config.PiperBinary, e.args(outPath)...)
+	cmd.Stdin = strings.NewReader(text)
+	var errOut bytes.Buffer
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, lastLine(errOut.String()))
+	}
+	return nil
+}
+
+func (e *PiperEngine) args(outPath string) []string {
+	args := []string{
+		"--model", e.config.PiperModel,
+		"--output_file", outPath,
+		"--length_scale", formatFloat(e.config.PiperLengthScale),
+		"--noise_scale", formatFloat(e.config.PiperNoiseScale),
+		"--noise_w", formatFloat(e.config.PiperNoiseW),
+	}
+	// Piper looks for <model>.json by default
+	if e.config.PiperConfig != "" {
+		args = append(args, "--config", e.config.PiperConfig)
+	}
+	if e.config.PiperSpeaker != "" {
+		args = append(args, "--speaker", e.config.PiperSpeaker)
+	}
+	return args
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
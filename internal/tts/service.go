@@ -2,61 +2,172 @@ package tts
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os/exec"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/g-laliotis/convertbox/internal/config"
 	"github.com/g-laliotis/convertbox/internal/logger"
+	"github.com/g-laliotis/convertbox/internal/textnorm"
 )
 
 type Service struct {
-	config *config.Config
-	logger *logger.Logger
+	config     *config.Config
+	logger     *logger.Logger
+	normalizer *textnorm.Normalizer
+	engines    []Engine
 }
 
 func NewService(cfg *config.Config, log *logger.Logger) *Service {
+	normalizer, err := textnorm.Load(cfg.LexiconPath)
+	if err != nil {
+		log.Warning("Pronunciation lexicon not loaded, using built-in rules only: %v", err)
+		normalizer = textnorm.New(nil)
+	}
+
+	var engines []Engine
+	for _, name := range engineOrder(cfg) {
+		factory, ok := registry[name]
+		if !ok {
+			log.Warning("Unknown TTS engine %q (available: %s)", name, strings.Join(Engines(), ", "))
+			continue
+		}
+		engines = append(engines, factory(cfg))
+	}
+
 	return &Service{
-		config: cfg,
-		logger: log,
+		config:     cfg,
+		logger:     log,
+		normalizer: normalizer,
+		engines:    engines,
+	}
+}
+
+// Synthesize speaks text into outPath sentence by sentence, joining the
+// clips with a short pause and writing a timing manifest next to the WAV.
+// Each configured engine is tried in order so the whole narration keeps one
+// voice. Sentences are normalized for pronunciation; the manifest keeps the
+// original text for captions. Batch engines such as Coqui receive every
+// sentence in one call so their model is loaded only once.
+func (s *Service) Synthesize(ctx context.Context, text, outPath string) (*Manifest, error) {
+	sentences := textnorm.SplitSentences(text)
+	s.logger.Info("Synthesizing speech (%d sentences, %d chars)", len(sentences), len(text))
+
+	if len(sentences) == 0 {
+		return nil, errors.New("no text to synthesize")
 	}
+	if len(s.engines) == 0 {
+		return nil, errors.New("no TTS engines configured")
+	}
+
+	var lastErr error
+	for i, engine := range s.engines {
+		manifest, err := s.synthesizeWith(ctx, engine, sentences, outPath)
+		if err == nil {
+			s.logger.Info("Narration synthesized with %s (%.1fs)", engine.Name(), manifest.Duration)
+			return manifest, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		lastErr = fmt.Errorf("%s: %w", engine.Name(), err)
+		if i+1 < len(s.engines) {
+			s.logger.Warning("%s failed, falling back to %s: %v", engine.Name(), s.engines[i+1].Name(), err)
+		}
+	}
+	return nil, fmt.Errorf("all TTS engines failed, last error: %w", lastErr)
 }
 
-func (s *Service) Synthesize(ctx context.Context, text, outPath string) error {
-	s.logger.Info("Synthesizing speech (%d chars)", len(text))
+func (s *Service) synthesizeWith(ctx context.Context, engine Engine, sentences []string, outPath string) (*Manifest, error) {
+	chunkDir := filepath.Join(filepath.Dir(outPath), "tts_chunks")
+	if err := os.MkdirAll(chunkDir, 0755); err != nil {
+		return nil, err
+	}
+
+	// Normalize first so batch engines get every sentence in one call
+	var texts, spokens, chunkPaths []string
+	for i, sentence := range sentences {
+		spoken := s.normalizer.Normalize(sentence)
+		if spoken == "" {
+			continue
+		}
+		texts = append(texts, sentence)
+		spokens = append(spokens, spoken)
+		chunkPaths = append(chunkPaths, filepath.Join(chunkDir, fmt.Sprintf("%s_%03d.wav", engine.Name(), i)))
+	}
+	if len(spokens) == 0 {
+		return nil, errors.New("no speakable sentences")
+	}
+
+	batch, isBatch := engine.(BatchEngine)
+	if isBatch {
+		if err := batch.SpeakBatch(ctx, spokens, chunkPaths); err != nil {
+			return nil, err
+		}
+	}
+
+	pause := time.Duration(s.config.TTSSentencePauseMs) * time.Millisecond
+	manifest := &Manifest{Engine: engine.Name(), Audio: outPath}
+	var format *wavAudio
+	var data []byte
+
+	for i, spoken := range spokens {
+		chunkPath := chunkPaths[i]
+		if !isBatch {
+			if err := engine.Speak(ctx, spoken, chunkPath); err != nil {
+				return nil, fmt.Errorf("sentence %d: %w", i+1, err)
+			}
+		}
+		clip, err := readWAV(chunkPath)
+		if err != nil {
+			return nil, fmt.Errorf("sentence %d: %w", i+1, err)
+		}
 
-	if s.config.TTSEngine == "coqui" {
-		if err := s.coquiSpeak(ctx, text, outPath); err != nil {
-			s.logger.Warning("Coqui failed, falling back to eSpeak: %v", err)
-			return s.eSpeak(ctx, text, outPath)
+		if format == nil {
+			format = clip
+		} else if !format.sameFormat(clip) {
+			return nil, fmt.Errorf("sentence %d: audio format changed mid-narration", i+1)
+		} else {
+			data = append(data, format.silence(pause)...)
 		}
-		return nil
+
+		start := format.duration(len(data))
+		data = append(data, clip.data...)
+		manifest.Sentences = append(manifest.Sentences, SentenceTiming{
+			Index:  len(manifest.Sentences),
+			Text:   texts[i],
+			Spoken: spoken,
+			Start:  seconds(start),
+			End:    seconds(format.duration(len(data))),
+		})
 	}
-	return s.eSpeak(ctx, text, outPath)
+
+	if err := writeWAV(outPath, format.format, data); err != nil {
+		return nil, err
+	}
+	manifest.Duration = seconds(format.duration(len(data)))
+
+	if err := manifest.save(ManifestPath(outPath)); err != nil {
+		return nil, fmt.Errorf("save timing manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// ListModels returns the TTS models Coqui knows about
+func (s *Service) ListModels(ctx context.Context) ([]string, error) {
+	return NewCoquiEngine(s.config).ListModels(ctx)
 }
 
-func (s *Service) coquiSpeak(ctx context.Context, text, outPath string) error {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Minute)
-	defer cancel()
-
-	// Use better voice model for tech content
-	cmd := exec.CommandContext(ctx, "tts",
-		"--text", text,
-		"--model_name", "tts_models/en/ljspeech/tacotron2-DDC",
-		"--out_path", outPath,
-	)
-	return cmd.Run()
+// ListSpeakers returns the speaker IDs of a multi-speaker Coqui model
+func (s *Service) ListSpeakers(ctx context.Context, model string) ([]string, error) {
+	return NewCoquiEngine(s.config).ListSpeakers(ctx, model)
 }
 
-func (s *Service) eSpeak(ctx context.Context, text, outPath string) error {
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "espeak-ng",
-		"-v", s.config.ESpeakVoice,
-		"-s", fmt.Sprintf("%d", s.config.ESpeakSpeed),
-		"-w", outPath,
-		text,
-	)
-	return cmd.Run()
-}
\ No newline at end of file
+// ListLanguages returns the language IDs of a multilingual Coqui model
+func (s *Service) ListLanguages(ctx context.Context, model string) ([]string, error) {
+	return NewCoquiEngine(s.config).ListLanguages(ctx, model)
+}
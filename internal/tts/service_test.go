@@ -0,0 +1,198 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/g-laliotis/convertbox/internal/config"
+	"github.com/g-laliotis/convertbox/internal/logger"
+)
+
+// fakeEngine records the text it was given and writes 100ms of 1kHz mono
+// 16-bit audio per word, or fails when err is set
+type fakeEngine struct {
+	name  string
+	err   error
+	calls *[]string
+	text  *string
+}
+
+func (e *fakeEngine) Name() string {
+	return e.name
+}
+
+func (e *fakeEngine) Speak(ctx context.Context, text, outPath string) error {
+	*e.calls = append(*e.calls, e.name)
+	*e.text = text
+	if e.err != nil {
+		return e.err
+	}
+	return writeWAV(outPath, testFormat, make([]byte, 200*len(strings.Fields(text))))
+}
+
+// testFormat is PCM, mono, 1000 Hz, 2000 bytes/s, block align 2, 16 bits
+var testFormat = []byte{1, 0, 1, 0, 0xe8, 0x03, 0, 0, 0xd0, 0x07, 0, 0, 2, 0, 16, 0}
+
+func TestService_SynthesizeFallbackOrder(t *testing.T) {
+	var calls []string
+	var spoken string
+	Register("fake-broken", func(cfg *config.Config) Engine {
+		return &fakeEngine{name: "fake-broken", err: errors.New("boom"), calls: &calls, text: &spoken}
+	})
+	Register("fake-ok", func(cfg *config.Config) Engine {
+		return &fakeEngine{name: "fake-ok", calls: &calls, text: &spoken}
+	})
+	defer delete(registry, "fake-broken")
+	defer delete(registry, "fake-ok")
+
+	cfg := &config.Config{TTSEngines: []string{"fake-broken", "missing", "FAKE-OK", "fake-broken"}}
+	service := NewService(cfg, logger.New())
+	outPath := filepath.Join(t.TempDir(), "narration.wav")
+
+	if _, err := service.Synthesize(context.Background(), "AI in 2025", outPath); err != nil {
+		t.Fatalf("Synthesize failed: %v", err)
+	}
+	if strings.Join(calls, ",") != "fake-broken,fake-ok" {
+		t.Errorf("engine calls = %v", calls)
+	}
+	if spoken != "AI in twenty twenty-five" {
+		t.Errorf("engines should receive normalized text, got %q", spoken)
+	}
+}
+
+func TestService_SynthesizeSentenceManifest(t *testing.T) {
+	var calls []string
+	var spoken string
+	Register("fake-ok", func(cfg *config.Config) Engine {
+		return &fakeEngine{name: "fake-ok", calls: &calls, text: &spoken}
+	})
+	defer delete(registry, "fake-ok")
+
+	cfg := &config.Config{TTSEngines: []string{"fake-ok"}, TTSSentencePauseMs: 500}
+	service := NewService(cfg, logger.New())
+	outPath := filepath.Join(t.TempDir(), "narration.wav")
+
+	manifest, err := service.Synthesize(context.Background(), "Meet A.I. tools. They cost $5!", outPath)
+	if err != nil {
+		t.Fatalf("Synthesize failed: %v", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("expected one engine call per sentence, got %d", len(calls))
+	}
+
+	// "Meet A.I. tools." is 3 words (0.3s), pause 0.5s, "They cost five dollars!" is 4 words (0.4s)
+	want := []SentenceTiming{
+		{Index: 0, Text: "Meet A.I. tools.", Spoken: "Meet A.I. tools.", Start: 0, End: 0.3},
+		{Index: 1, Text: "They cost $5!", Spoken: "They cost five dollars!", Start: 0.8, End: 1.2},
+	}
+	for i, timing := range manifest.Sentences {
+		if timing != want[i] {
+			t.Errorf("sentence %d = %+v, want %+v", i, timing, want[i])
+		}
+	}
+	if manifest.Duration != 1.2 {
+		t.Errorf("Duration = %v, want 1.2", manifest.Duration)
+	}
+
+	audio, err := readWAV(outPath)
+	if err != nil {
+		t.Fatalf("narration is not a valid WAV: %v", err)
+	}
+	if len(audio.data) != 2400 {
+		t.Errorf("narration has %d data bytes, want 2400", len(audio.data))
+	}
+
+	saved, err := LoadManifest(ManifestPath(outPath))
+	if err != nil || len(saved.Sentences) != 2 || saved.Engine != "fake-ok" {
+		t.Errorf("saved manifest = %+v, %v", saved, err)
+	}
+}
+
+// fakeBatchEngine counts SpeakBatch calls and never expects Speak
+type fakeBatchEngine struct {
+	fakeEngine
+	batches int
+}
+
+func (e *fakeBatchEngine) SpeakBatch(ctx context.Context, texts, outPaths []string) error {
+	e.batches++
+	for i, text := range texts {
+		if err := e.fakeEngine.Speak(ctx, text, outPaths[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *fakeBatchEngine) Speak(ctx context.Context, text, outPath string) error {
+	return errors.New("Speak called on a batch engine")
+}
+
+func TestService_SynthesizeBatch(t *testing.T) {
+	var calls []string
+	var spoken string
+	engine := &fakeBatchEngine{fakeEngine: fakeEngine{name: "fake-batch", calls: &calls, text: &spoken}}
+	Register("fake-batch", func(cfg *config.Config) Engine { return engine })
+	defer delete(registry, "fake-batch")
+
+	service := NewService(&config.Config{TTSEngines: []string{"fake-batch"}}, logger.New())
+	manifest, err := service.Synthesize(context.Background(), "One two. Three four five.", filepath.Join(t.TempDir(), "out.wav"))
+	if err != nil {
+		t.Fatalf("Synthesize failed: %v", err)
+	}
+	if engine.batches != 1 || len(calls) != 2 {
+		t.Errorf("batches = %d, sentences = %d, want 1 batch of 2", engine.batches, len(calls))
+	}
+	if len(manifest.Sentences) != 2 || manifest.Sentences[1].End != 0.5 {
+		t.Errorf("manifest = %+v", manifest.Sentences)
+	}
+}
+
+func TestService_SynthesizeAllFail(t *testing.T) {
+	var calls []string
+	var spoken string
+	Register("fake-broken", func(cfg *config.Config) Engine {
+		return &fakeEngine{name: "fake-broken", err: errors.New("boom"), calls: &calls, text: &spoken}
+	})
+	defer delete(registry, "fake-broken")
+
+	service := NewService(&config.Config{TTSEngines: []string{"fake-broken"}}, logger.New())
+	_, err := service.Synthesize(context.Background(), "hello", filepath.Join(t.TempDir(), "out.wav"))
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected engine error, got %v", err)
+	}
+}
+
+func TestEngineOrder(t *testing.T) {
+	tests := []struct {
+		cfg  config.Config
+		want string
+	}{
+		{config.Config{TTSEngine: "coqui"}, "coqui,espeak"},
+		{config.Config{TTSEngine: "espeak"}, "espeak"},
+		{config.Config{TTSEngine: "coqui", TTSEngines: []string{"Piper", " coqui", "espeak"}}, "piper,coqui,espeak"},
+	}
+
+	for _, test := range tests {
+		if got := strings.Join(engineOrder(&test.cfg), ","); got != test.want {
+			t.Errorf("engineOrder(%+v) = %s, want %s", test.cfg, got, test.want)
+		}
+	}
+}
+
+func TestPiperEngine_Args(t *testing.T) {
+	cfg := &config.Config{PiperModel: "voice.onnx", PiperLengthScale: 1.1, PiperNoiseScale: 0.667, PiperNoiseW: 0.8, PiperSpeaker: "3"}
+	args := strings.Join(NewPiperEngine(cfg).args("out.wav"), " ")
+
+	for _, want := range []string{"--model voice.onnx", "--output_file out.wav", "--length_scale 1.1", "--noise_scale 0.667", "--noise_w 0.8", "--speaker 3"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("args %q missing %q", args, want)
+		}
+	}
+	if strings.Contains(args, "--config") {
+		t.Errorf("--config should be omitted when unset: %s", args)
+	}
+}
@@ -0,0 +1,105 @@
+package tts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// wavAudio is the fmt chunk and raw sample data of a WAV file
+type wavAudio struct {
+	format []byte
+	data   []byte
+}
+
+func (w *wavAudio) audioFormat() uint16   { return binary.LittleEndian.Uint16(w.format[0:2]) }
+func (w *wavAudio) channels() uint16      { return binary.LittleEndian.Uint16(w.format[2:4]) }
+func (w *wavAudio) sampleRate() uint32    { return binary.LittleEndian.Uint32(w.format[4:8]) }
+func (w *wavAudio) byteRate() uint32      { return binary.LittleEndian.Uint32(w.format[8:12]) }
+func (w *wavAudio) blockAlign() uint16    { return binary.LittleEndian.Uint16(w.format[12:14]) }
+func (w *wavAudio) bitsPerSample() uint16 { return binary.LittleEndian.Uint16(w.format[14:16]) }
+
+// duration returns the playback length of data bytes in this format
+func (w *wavAudio) duration(bytes int) time.Duration {
+	if w.byteRate() == 0 {
+		return 0
+	}
+	return time.Duration(int64(bytes) * int64(time.Second) / int64(w.byteRate()))
+}
+
+// silence returns whole frames of silence lasting about d
+func (w *wavAudio) silence(d time.Duration) []byte {
+	frames := int64(d) * int64(w.sampleRate()) / int64(time.Second)
+	buf := make([]byte, frames*int64(w.blockAlign()))
+	if w.audioFormat() == 1 && w.bitsPerSample() == 8 {
+		// 8-bit PCM is unsigned, so silence sits at the midpoint
+		for i := range buf {
+			buf[i] = 0x80
+		}
+	}
+	return buf
+}
+
+func (w *wavAudio) sameFormat(other *wavAudio) bool {
+	return w.audioFormat() == other.audioFormat() &&
+		w.channels() == other.channels() &&
+		w.sampleRate() == other.sampleRate() &&
+		w.bitsPerSample() == other.bitsPerSample()
+}
+
+func readWAV(path string) (*wavAudio, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) < 12 || string(raw[0:4]) != "RIFF" || string(raw[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("%s is not a WAV file", path)
+	}
+
+	audio := &wavAudio{}
+	for pos := 12; pos+8 <= len(raw); {
+		id := string(raw[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(raw[pos+4 : pos+8]))
+		pos += 8
+		// Streaming writers leave the size unset, so clamp to the file
+		if size < 0 || pos+size > len(raw) {
+			size = len(raw) - pos
+		}
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, fmt.Errorf("%s has a truncated fmt chunk", path)
+			}
+			audio.format = raw[pos : pos+size]
+		case "data":
+			audio.data = raw[pos : pos+size]
+		}
+		pos += size + size%2
+	}
+
+	if audio.format == nil || audio.data == nil {
+		return nil, fmt.Errorf("%s is missing fmt or data chunk", path)
+	}
+	if audio.blockAlign() == 0 {
+		return nil, errors.New("invalid WAV block alignment")
+	}
+	return audio, nil
+}
+
+func writeWAV(path string, format, data []byte) error {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(4+8+len(format)+8+len(data)))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(format)))
+	buf.Write(format)
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
@@ -0,0 +1,85 @@
+package visuals
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// KeywordMap maps visual concepts to the asset tags that can show them
+type KeywordMap map[string][]string
+
+// LoadKeywordMap reads a keyword map file of "concept = tag, tag" lines
+func LoadKeywordMap(path string) (KeywordMap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseKeywordMap(f)
+}
+
+// ParseKeywordMap parses "concept = tag, tag" lines, skipping blanks and #
+// comments. Concepts may be phrases; both sides are case-insensitive.
+func ParseKeywordMap(r io.Reader) (KeywordMap, error) {
+	keywordMap := make(KeywordMap)
+	scanner := bufio.NewScanner(r)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		concept, tags, ok := strings.Cut(text, "=")
+		concept = strings.ToLower(strings.Join(strings.Fields(concept), " "))
+		var parsed []string
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+				parsed = append(parsed, tag)
+			}
+		}
+		if !ok || concept == "" || len(parsed) == 0 {
+			return nil, fmt.Errorf("keyword map line %d: want \"concept = tag, tag\", got %q", line, text)
+		}
+		keywordMap[concept] = append(keywordMap[concept], parsed...)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return keywordMap, nil
+}
+
+// Tags expands keywords into the terms to match against asset tags: each
+// keyword, the words of multi-word keywords, and whatever the map gives for
+// either
+func (m KeywordMap) Tags(keywords []string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	add := func(tag string) {
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(strings.Join(strings.Fields(keyword), " "))
+		candidates := []string{keyword}
+		if words := strings.Fields(keyword); len(words) > 1 {
+			candidates = append(candidates, words...)
+		}
+		for _, candidate := range candidates {
+			add(candidate)
+			for _, tag := range m[candidate] {
+				add(tag)
+			}
+		}
+	}
+	return tags
+}
@@ -0,0 +1,90 @@
+// Package visuals finds the visual concepts in a script and maps them to
+// the tags of the background asset library
+package visuals
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// stopWords carry no visual meaning and are never keywords
+var stopWords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		a about after again all also am an and any are as at be because been before being
+		but by can could did do does doing don't down during each even ever every few for
+		from get gets got had has have having he her here hers him his how i if in into is
+		it it's its just know let's like make makes many may me more most much must my
+		never new next no not now of off on once one only or other our out over own really
+		right same see she should so some still such than that that's the their them then
+		there these they thing things think this those through to too try under until up
+		us use used using very want was way we well were what when where which while who
+		why will with without would you you'll you're your yours
+		did don't doesn't can't won't isn't aren't here's there's what's
+		today way ways lot lots going something anything everything nothing
+		subscribe follow comment`) {
+		stopWords[word] = true
+	}
+}
+
+// Extract picks up to n keywords per sentence by TF-IDF, treating each
+// sentence as a document so words the whole script repeats rank below the
+// words that set a sentence apart
+func Extract(sentences []string, n int) [][]string {
+	documents := make([][]string, len(sentences))
+	frequency := map[string]int{}
+	for i, sentence := range sentences {
+		documents[i] = terms(sentence)
+		seen := map[string]bool{}
+		for _, term := range documents[i] {
+			if !seen[term] {
+				seen[term] = true
+				frequency[term]++
+			}
+		}
+	}
+
+	keywords := make([][]string, len(sentences))
+	for i, document := range documents {
+		counts := map[string]int{}
+		var order []string
+		for _, term := range document {
+			if counts[term] == 0 {
+				order = append(order, term)
+			}
+			counts[term]++
+		}
+
+		scores := map[string]float64{}
+		for _, term := range order {
+			tf := float64(counts[term]) / float64(len(document))
+			idf := math.Log(float64(1+len(documents))/float64(1+frequency[term])) + 1
+			scores[term] = tf * idf
+		}
+		// Stable sort keeps first mentions ahead on equal scores
+		sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] > scores[order[b]] })
+		if len(order) > n {
+			order = order[:n]
+		}
+		keywords[i] = order
+	}
+	return keywords
+}
+
+// terms returns the lowercase candidate words of a sentence
+func terms(sentence string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(sentence), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	}) {
+		word = strings.Trim(word, "'")
+		if len([]rune(word)) < 3 || stopWords[word] || !strings.ContainsFunc(word, unicode.IsLetter) {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
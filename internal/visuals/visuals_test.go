@@ -0,0 +1,56 @@
+package visuals
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	sentences := []string{
+		"Robots are learning to cook dinner.",
+		"These robots chop onions faster than any chef.",
+		"Subscribe for more!",
+	}
+	got := Extract(sentences, 2)
+
+	want := [][]string{
+		{"learning", "cook"}, // robots appears twice, so it ranks lower
+		{"chop", "onions"},
+		nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract = %q, want %q", got, want)
+	}
+}
+
+func TestParseKeywordMap(t *testing.T) {
+	keywordMap, err := ParseKeywordMap(strings.NewReader(`# comment
+neural = ai, Robot
+
+City  Skyline = city, night
+neural = brain
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := KeywordMap{"neural": {"ai", "robot", "brain"}, "city skyline": {"city", "night"}}
+	if !reflect.DeepEqual(keywordMap, want) {
+		t.Errorf("ParseKeywordMap = %v, want %v", keywordMap, want)
+	}
+
+	for _, bad := range []string{"neural", "= ai", "neural = , "} {
+		if _, err := ParseKeywordMap(strings.NewReader(bad)); err == nil {
+			t.Errorf("accepted %q", bad)
+		}
+	}
+}
+
+func TestKeywordMap_Tags(t *testing.T) {
+	keywordMap := KeywordMap{"neural": {"ai"}, "city skyline": {"city"}, "laptop": {"tech"}}
+	got := keywordMap.Tags([]string{"City Skyline", "neural network", "ai"})
+	want := []string{"city skyline", "city", "skyline", "neural network", "neural", "ai", "network"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tags = %q, want %q", got, want)
+	}
+}